@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_gpg_key" "signing" {
+  group_path  = "<group_path>"
+  fingerprint = "<fingerprint>"
+}
+
+output "gpg_key_id" {
+  value = data.tharsis_gpg_key.signing.gpg_key_id
+}
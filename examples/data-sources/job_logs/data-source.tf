@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_job_logs" "this" {
+  job_id = "<job_id>"
+
+  # Optionally, read only a range of the logs.
+  # start = 0
+  # limit = 1024
+}
+
+output "logs" {
+  value = data.tharsis_job_logs.this.logs
+}
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_namespace_memberships" "this" {
+  namespace_path = "<group_or_workspace_path>"
+}
+
+output "owner_membership_ids" {
+  value = [for m in data.tharsis_namespace_memberships.this.memberships : m.id if m.role == "owner"]
+}
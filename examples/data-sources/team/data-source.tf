@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_team" "platform" {
+  name = "<team_name>"
+}
+
+output "team_id" {
+  value = data.tharsis_team.platform.id
+}
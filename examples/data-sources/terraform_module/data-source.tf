@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_terraform_module" "network" {
+  registry_namespace = "<registry_namespace>"
+  name               = "network"
+  system             = "aws"
+}
+
+output "module_id" {
+  value = data.tharsis_terraform_module.network.id
+}
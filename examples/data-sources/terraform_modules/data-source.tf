@@ -0,0 +1,21 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_terraform_modules" "aws" {
+  search     = "network"
+  group_path = "<group_path>"
+}
+
+output "module_paths" {
+  value = [for module in data.tharsis_terraform_modules.aws.modules : module.resource_path]
+}
@@ -0,0 +1,24 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_terraform_provider_versions" "example" {
+  registry_namespace = "<registry_namespace>"
+  name               = "example"
+}
+
+output "latest_linux_shasum" {
+  value = one([
+    for platform in data.tharsis_terraform_provider_versions.example.versions[length(data.tharsis_terraform_provider_versions.example.versions) - 1].platforms :
+    platform.shasum if platform.os == "linux" && platform.arch == "amd64"
+  ])
+}
@@ -0,0 +1,27 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_users" "this" {
+  usernames = ["<username>", "<email>"]
+
+  lifecycle {
+    postcondition {
+      condition     = length(self.missing) == 0 && alltrue(self.users[*].active)
+      error_message = "All users must exist and be active."
+    }
+  }
+}
+
+output "usernames" {
+  value = data.tharsis_users.this.users[*].username
+}
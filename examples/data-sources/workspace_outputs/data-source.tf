@@ -33,6 +33,15 @@ data "tharsis_workspace_outputs" "this" {
 #   path = "../sub-group/workspace"
 # }
 
+# To read the outputs of a workspace whose first run may not have finished yet,
+# wait for the output to exist.
+#
+# data "tharsis_workspace_outputs" "this" {
+#   path            = "group/sub-group/workspace"
+#   wait_for_output = "output_name"
+#   wait_timeout    = "30m"
+# }
+
 output "str" {
   value = data.tharsis_workspace_outputs.this.outputs.output_name
 }
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    tharsis = {
+      source = "registry.terraform.io/martian-cloud/tharsis"
+    }
+  }
+}
+
+provider "tharsis" {
+  host         = "<tharsis_api_host>"
+  static_token = "<static_token>"
+}
+
+data "tharsis_workspaces" "this" {
+  group_path = "<group_path>"
+}
+
+output "workspace_paths" {
+  value = data.tharsis_workspaces.this.workspaces[*].full_path
+}
@@ -10,3 +10,15 @@ provider "tharsis" {
 #   service_account_path  = "<service_account_path>"
 #   service_account_token = "<service_account_token>"
 # }
+
+# # Tharsis provider logging in as a service account with a CI job's OIDC token
+# provider "tharsis" {
+#   host                 = "<tharsis_api_host>"
+#   service_account_path = "<service_account_path>"
+#   oidc_token_file      = "<path_to_oidc_token_file>"
+# }
+
+# # Tharsis provider using the host and token of a Tharsis CLI profile
+# provider "tharsis" {
+#   profile = "<profile_name>"
+# }
@@ -0,0 +1,2 @@
+# Adopt the module currently applied to a workspace, using the workspace's full path.
+terraform import tharsis_apply_module.example my-group/my-workspace
@@ -0,0 +1,5 @@
+# Import a managed identity by its resource path, which is the path of its group plus its name.
+terraform import tharsis_managed_identity.example my-group/my-managed-identity
+
+# Or by its ID.
+terraform import tharsis_managed_identity.example 9f1f0c2e-6c55-4d7e-8a6b-2f4a0e6b3c71
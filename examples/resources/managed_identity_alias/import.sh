@@ -0,0 +1,5 @@
+# Import a managed identity alias by its resource path, which is the path of its group plus its name.
+terraform import tharsis_managed_identity_alias.example my-group/my-alias
+
+# Or by its ID.
+terraform import tharsis_managed_identity_alias.example c0a7e3b4-1d2f-4f6e-9b3a-5e8d7c6b4a20
@@ -0,0 +1,5 @@
+# Import a service account by its resource path, which is the path of its group plus its name.
+terraform import tharsis_service_account.example my-group/my-service-account
+
+# Or by its ID.
+terraform import tharsis_service_account.example 4b2b16a9-1e37-4c6b-9b8f-3c8f8e7c2d10
@@ -0,0 +1,5 @@
+# Import a Terraform module by its resource path, which is the path of its group plus its name and system.
+terraform import tharsis_terraform_module.example my-group/my-module/aws
+
+# Or by its ID.
+terraform import tharsis_terraform_module.example 5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d
@@ -0,0 +1,5 @@
+# Import a Terraform provider by its resource path, which is the path of its group plus its name.
+terraform import tharsis_terraform_provider.example my-group/my-provider
+
+# Or by its ID.
+terraform import tharsis_terraform_provider.example 7d6c5b4a-3f2e-4d1c-9b0a-8f7e6d5c4b3a
@@ -0,0 +1,3 @@
+# Import a Terraform provider version mirror by the group path, registry hostname,
+# registry namespace, type and version.
+terraform import tharsis_terraform_provider_version_mirror.example my-group/registry.terraform.io/hashicorp/aws/5.0.0
@@ -0,0 +1,5 @@
+# Import a variable by its resource path, which is the path of its namespace plus its category and key.
+terraform import tharsis_variable.example my-group/terraform/my-key
+
+# Or by its ID.
+terraform import tharsis_variable.example 1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e
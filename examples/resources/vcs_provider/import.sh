@@ -0,0 +1,5 @@
+# Import a VCS provider by its resource path, which is the path of its group plus its name.
+terraform import tharsis_vcs_provider.example my-group/my-vcs-provider
+
+# Or by its ID.
+terraform import tharsis_vcs_provider.example 2e6d8f1a-7b3c-4a59-8e0d-1c2b3a4f5e6d
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// apiErrorKind is the class of an error returned by the Tharsis API.
+type apiErrorKind string
+
+// The classes of API errors that have their own remediation hints.
+const (
+	apiErrorUnknown     apiErrorKind = ""
+	apiErrorNotFound    apiErrorKind = "not_found"
+	apiErrorForbidden   apiErrorKind = "forbidden"
+	apiErrorConflict    apiErrorKind = "conflict"
+	apiErrorValidation  apiErrorKind = "validation"
+	apiErrorRateLimited apiErrorKind = "rate_limited"
+)
+
+// apiErrorHints tell users what to do about each class of API error.
+var apiErrorHints = map[apiErrorKind]string{
+	apiErrorNotFound: "The resource doesn't exist, or the provider's credentials can't see it. " +
+		"Check the path, and that the user or service account has access to the group.",
+	apiErrorForbidden: "The provider's user or service account isn't allowed to do this. " +
+		"Check that it has a role with the needed permissions in the group.",
+	apiErrorConflict: "The resource conflicts with one that already exists, or was changed outside of Terraform. " +
+		"Import the existing resource or choose another name, then apply again.",
+	apiErrorValidation:  "Tharsis rejected the request. Check the resource's arguments against the provider documentation.",
+	apiErrorRateLimited: "Tharsis is rate limiting requests. Apply with less parallelism, or wait and apply again.",
+}
+
+// classifyAPIError returns the class of an error returned by the Tharsis API, from the code of the SDK's error.
+func classifyAPIError(err error) apiErrorKind {
+	var tErr *ttypes.Error
+	if !errors.As(err, &tErr) {
+		return apiErrorUnknown
+	}
+
+	switch tErr.Code {
+	case ttypes.ErrNotFound:
+		return apiErrorNotFound
+	case ttypes.ErrForbidden, ttypes.ErrUnauthorized:
+		return apiErrorForbidden
+	case ttypes.ErrConflict, ttypes.ErrOptimisticLock:
+		return apiErrorConflict
+	case ttypes.ErrBadRequest:
+		return apiErrorValidation
+	case ttypes.ErrTooManyRequests:
+		return apiErrorRateLimited
+	}
+
+	return apiErrorUnknown
+}
+
+// apiErrorDiagnostic returns the diagnostic for a failed API call.  Its detail has the error,
+// the path or ID of the resource the call was about, if known, and a hint for the class of error.
+func apiErrorDiagnostic(summary string, err error, resource string) diag.Diagnostic {
+	detail := err.Error()
+
+	if resource != "" {
+		detail += "\n\nResource: " + resource
+	}
+
+	if hint, ok := apiErrorHints[classifyAPIError(err)]; ok {
+		detail += "\n\n" + hint
+	}
+
+	return diag.NewErrorDiagnostic(summary, detail)
+}
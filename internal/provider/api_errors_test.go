@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+func Test_classifyAPIError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want apiErrorKind
+	}{
+		{
+			name: "No error",
+			err:  nil,
+			want: apiErrorUnknown,
+		},
+		{
+			name: "Not found",
+			err:  &ttypes.Error{Code: ttypes.ErrNotFound, Msg: "workspace not found"},
+			want: apiErrorNotFound,
+		},
+		{
+			name: "Forbidden",
+			err:  &ttypes.Error{Code: ttypes.ErrForbidden, Msg: "user does not have permission to create workspaces in group"},
+			want: apiErrorForbidden,
+		},
+		{
+			name: "Unauthorized",
+			err:  &ttypes.Error{Code: ttypes.ErrUnauthorized},
+			want: apiErrorForbidden,
+		},
+		{
+			name: "Conflict",
+			err:  &ttypes.Error{Code: ttypes.ErrConflict, Msg: "workspace with name prod already exists in group"},
+			want: apiErrorConflict,
+		},
+		{
+			name: "Optimistic lock",
+			err:  &ttypes.Error{Code: ttypes.ErrOptimisticLock},
+			want: apiErrorConflict,
+		},
+		{
+			name: "Validation",
+			err:  &ttypes.Error{Code: ttypes.ErrBadRequest, Msg: "invalid max job duration"},
+			want: apiErrorValidation,
+		},
+		{
+			name: "Rate limited",
+			err:  &ttypes.Error{Code: ttypes.ErrTooManyRequests},
+			want: apiErrorRateLimited,
+		},
+		{
+			name: "Wrapped",
+			err:  fmt.Errorf("failed to create workspace: %w", &ttypes.Error{Code: ttypes.ErrConflict}),
+			want: apiErrorConflict,
+		},
+		{
+			name: "Messages aren't classified",
+			err:  errors.New("Forbidden: workspace with name prod already exists"),
+			want: apiErrorUnknown,
+		},
+		{
+			name: "Other codes",
+			err:  &ttypes.Error{Code: ttypes.ErrInternal, Msg: "something went wrong"},
+			want: apiErrorUnknown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyAPIError(tt.err); got != tt.want {
+				t.Errorf("classifyAPIError() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_apiErrorDiagnostic(t *testing.T) {
+	d := apiErrorDiagnostic("Error creating workspace",
+		&ttypes.Error{Code: ttypes.ErrConflict, Msg: "workspace with name prod already exists"}, "group/sub")
+
+	if d.Summary() != "Error creating workspace" {
+		t.Errorf("Summary() = %q, want %q", d.Summary(), "Error creating workspace")
+	}
+	for _, want := range []string{"already exists", "Resource: group/sub", apiErrorHints[apiErrorConflict]} {
+		if !strings.Contains(d.Detail(), want) {
+			t.Errorf("Detail() = %q, want it to contain %q", d.Detail(), want)
+		}
+	}
+
+	d = apiErrorDiagnostic("Error reading workspace", errors.New("something went wrong"), "")
+	if d.Detail() != "something went wrong" {
+		t.Errorf("Detail() = %q, want only the error", d.Detail())
+	}
+}
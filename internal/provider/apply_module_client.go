@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"context"
+
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	sdktypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// runService is the part of the Tharsis run API used by tharsis_apply_module.
+type runService interface {
+	GetRun(ctx context.Context, input *sdktypes.GetRunInput) (*sdktypes.Run, error)
+	GetRunVariables(ctx context.Context, input *sdktypes.GetRunInput) ([]sdktypes.RunVariable, error)
+	CreateRun(ctx context.Context, input *sdktypes.CreateRunInput) (*sdktypes.Run, error)
+	ApplyRun(ctx context.Context, input *sdktypes.ApplyRunInput) (*sdktypes.Run, error)
+	CancelRun(ctx context.Context, input *sdktypes.CancelRunInput) (*sdktypes.Run, error)
+}
+
+// jobService is the part of the Tharsis job API used by tharsis_apply_module.
+type jobService interface {
+	GetJob(ctx context.Context, input *sdktypes.GetJobInput) (*sdktypes.Job, error)
+	GetJobLogs(ctx context.Context, input *sdktypes.GetJobLogsInput) (*sdktypes.JobLogs, error)
+}
+
+// workspaceService is the part of the Tharsis workspace API used by tharsis_apply_module.
+type workspaceService interface {
+	GetWorkspace(ctx context.Context, input *sdktypes.GetWorkspaceInput) (*sdktypes.Workspace, error)
+}
+
+// configurationVersionService is the part of the Tharsis configuration version API used by tharsis_apply_module.
+type configurationVersionService interface {
+	CreateConfigurationVersion(ctx context.Context,
+		input *sdktypes.CreateConfigurationVersionInput) (*sdktypes.ConfigurationVersion, error)
+	GetConfigurationVersion(ctx context.Context,
+		input *sdktypes.GetConfigurationVersionInput) (*sdktypes.ConfigurationVersion, error)
+	UploadConfigurationVersion(ctx context.Context, input *sdktypes.UploadConfigurationVersionInput) error
+}
+
+// applyModuleClient holds the Tharsis services used by tharsis_apply_module.
+// The fields are named after those of the SDK client, and unit tests replace them with fakes.
+type applyModuleClient struct {
+	Run                  runService
+	Job                  jobService
+	Workspaces           workspaceService
+	ConfigurationVersion configurationVersionService
+}
+
+// newApplyModuleClient returns the services of an SDK client, or nil if there's no client.
+func newApplyModuleClient(client *tharsis.Client) *applyModuleClient {
+	if client == nil {
+		return nil
+	}
+
+	return &applyModuleClient{
+		Run:                  client.Run,
+		Job:                  client.Job,
+		Workspaces:           client.Workspaces,
+		ConfigurationVersion: client.ConfigurationVersion,
+	}
+}
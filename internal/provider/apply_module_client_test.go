@@ -0,0 +1,439 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	sdktypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// errUnexpectedCall is returned by a mock service for a call the test didn't expect.
+var errUnexpectedCall = errors.New("unexpected call")
+
+// mockRunService is a runService whose calls are handled by the test.
+type mockRunService struct {
+	getRun          func(input *sdktypes.GetRunInput) (*sdktypes.Run, error)
+	getRunVariables func(input *sdktypes.GetRunInput) ([]sdktypes.RunVariable, error)
+	createRun       func(input *sdktypes.CreateRunInput) (*sdktypes.Run, error)
+	applyRun        func(input *sdktypes.ApplyRunInput) (*sdktypes.Run, error)
+	cancelRun       func(input *sdktypes.CancelRunInput) (*sdktypes.Run, error)
+}
+
+func (m *mockRunService) GetRun(_ context.Context, input *sdktypes.GetRunInput) (*sdktypes.Run, error) {
+	if m.getRun == nil {
+		return nil, errUnexpectedCall
+	}
+	return m.getRun(input)
+}
+
+func (m *mockRunService) GetRunVariables(_ context.Context, input *sdktypes.GetRunInput) ([]sdktypes.RunVariable, error) {
+	if m.getRunVariables == nil {
+		return nil, errUnexpectedCall
+	}
+	return m.getRunVariables(input)
+}
+
+func (m *mockRunService) CreateRun(_ context.Context, input *sdktypes.CreateRunInput) (*sdktypes.Run, error) {
+	if m.createRun == nil {
+		return nil, errUnexpectedCall
+	}
+	return m.createRun(input)
+}
+
+func (m *mockRunService) ApplyRun(_ context.Context, input *sdktypes.ApplyRunInput) (*sdktypes.Run, error) {
+	if m.applyRun == nil {
+		return nil, errUnexpectedCall
+	}
+	return m.applyRun(input)
+}
+
+func (m *mockRunService) CancelRun(_ context.Context, input *sdktypes.CancelRunInput) (*sdktypes.Run, error) {
+	if m.cancelRun == nil {
+		return nil, errUnexpectedCall
+	}
+	return m.cancelRun(input)
+}
+
+// mockJobService is a jobService whose calls are handled by the test.
+type mockJobService struct {
+	getJob     func(input *sdktypes.GetJobInput) (*sdktypes.Job, error)
+	getJobLogs func(input *sdktypes.GetJobLogsInput) (*sdktypes.JobLogs, error)
+}
+
+func (m *mockJobService) GetJob(_ context.Context, input *sdktypes.GetJobInput) (*sdktypes.Job, error) {
+	if m.getJob == nil {
+		return nil, errUnexpectedCall
+	}
+	return m.getJob(input)
+}
+
+func (m *mockJobService) GetJobLogs(_ context.Context, input *sdktypes.GetJobLogsInput) (*sdktypes.JobLogs, error) {
+	if m.getJobLogs == nil {
+		return nil, errUnexpectedCall
+	}
+	return m.getJobLogs(input)
+}
+
+// newMockJobLogs returns a getJobLogs handler serving the given logs, including the extra character the API returns.
+func newMockJobLogs(logs string) func(input *sdktypes.GetJobLogsInput) (*sdktypes.JobLogs, error) {
+	return func(input *sdktypes.GetJobLogsInput) (*sdktypes.JobLogs, error) {
+		start := int(input.Start)
+		end := len(logs)
+		if input.Limit != nil && start+int(*input.Limit)+1 < end {
+			end = start + int(*input.Limit) + 1
+		}
+		return &sdktypes.JobLogs{Logs: logs[start:end]}, nil
+	}
+}
+
+func Test_waitForJobCompletion(t *testing.T) {
+	notFound := &sdktypes.Error{Code: sdktypes.ErrNotFound, Msg: "job with id job-1 not found"}
+
+	tests := []struct {
+		name string
+		// statuses are returned by successive calls to GetJob.
+		statuses []string
+		// runStatus is the status of the job's run, which is checked while the job is queued.
+		runStatus sdktypes.RunStatus
+		getErr    error
+		jobID     *string
+		wantErr   string
+	}{
+		{
+			name:     "Returns once the job has finished",
+			statuses: []string{"queued", "running", "finished"},
+			jobID:    ptr.String("job-1"),
+		},
+		{
+			name:     "Returns once the job has ended another way",
+			statuses: []string{"queued", "errored"},
+			jobID:    ptr.String("job-1"),
+		},
+		{
+			name:      "Fails when the run ends before the job starts",
+			statuses:  []string{"queued"},
+			runStatus: sdktypes.RunCanceled,
+			jobID:     ptr.String("job-1"),
+			wantErr:   "run ID run-1 ended with status canceled before its plan job ID job-1 finished",
+		},
+		{
+			name:    "Fails when the job no longer exists",
+			getErr:  notFound,
+			jobID:   ptr.String("job-1"),
+			wantErr: "job ID job-1 no longer exists, so its run may have been deleted: " + notFound.Error(),
+		},
+		{
+			name:    "Requires a job ID",
+			wantErr: "nil job ID",
+		},
+		{
+			name:    "Returns an error that isn't retried",
+			getErr:  errors.New("job not found"),
+			jobID:   ptr.String("job-1"),
+			wantErr: "failed to get job ID job-1: job not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			runStatus := tt.runStatus
+			if runStatus == "" {
+				runStatus = sdktypes.RunPlanQueued
+			}
+			r := &applyModuleResource{client: &applyModuleClient{
+				Run: &mockRunService{
+					getRun: func(input *sdktypes.GetRunInput) (*sdktypes.Run, error) {
+						return &sdktypes.Run{Metadata: sdktypes.ResourceMetadata{ID: input.ID}, Status: runStatus}, nil
+					},
+				},
+				Job: &mockJobService{
+					getJob: func(input *sdktypes.GetJobInput) (*sdktypes.Job, error) {
+						if tt.getErr != nil {
+							return nil, tt.getErr
+						}
+						status := tt.statuses[calls]
+						calls++
+						return &sdktypes.Job{
+							Metadata: sdktypes.ResourceMetadata{ID: input.ID},
+							Status:   status,
+							Type:     sdktypes.JobPlanType,
+							RunID:    "run-1",
+						}, nil
+					},
+				},
+			}}
+
+			err := r.waitForJobCompletion(context.Background(), tt.jobID, &createRunInput{
+				pollInterval: time.Millisecond,
+				retry:        defaultRetryPolicy(),
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("waitForJobCompletion() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("waitForJobCompletion() unexpected error: %v", err)
+			}
+			if calls != len(tt.statuses) {
+				t.Errorf("waitForJobCompletion() called GetJob %d times, want %d", calls, len(tt.statuses))
+			}
+		})
+	}
+}
+
+func Test_waitForJobCompletion_contextExpired(t *testing.T) {
+	r := &applyModuleResource{client: &applyModuleClient{Job: &mockJobService{
+		getJob: func(input *sdktypes.GetJobInput) (*sdktypes.Job, error) {
+			return &sdktypes.Job{Status: "running"}, nil
+		},
+	}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := r.waitForJobCompletion(ctx, ptr.String("job-1"), &createRunInput{
+		pollInterval: time.Millisecond,
+		retry:        defaultRetryPolicy(),
+	})
+	if err == nil || !strings.Contains(err.Error(), "context expired") {
+		t.Errorf("waitForJobCompletion() error = %v, want a context expired error", err)
+	}
+}
+
+func Test_extractRunError(t *testing.T) {
+	// Long enough that the error is only found in the first of several chunks.
+	longLogs := "Initializing modules...\nError: Invalid provider configuration\n\nThe provider needs a region.\n" +
+		strings.Repeat("some other log line\n", (2*logChunkSize)/20)
+
+	tests := []struct {
+		name        string
+		run         *sdktypes.Run
+		logs        string
+		wantSummary string
+		wantWarning bool
+	}{
+		{
+			name: "Plan error in a single chunk",
+			run: &sdktypes.Run{
+				WorkspacePath: "group/workspace",
+				ModuleSource:  ptr.String("registry.example.com/group/module/aws"),
+				Plan:          &sdktypes.Plan{Status: sdktypes.PlanErrored, CurrentJobID: ptr.String("job-1")},
+			},
+			logs: "Initializing modules...\nError: Unsupported argument\n\nAn argument named \"foo\" is not expected here.\n" +
+				"Created new state version\n",
+			wantSummary: "Failed to plan module registry.example.com/group/module/aws in workspace group/workspace\n" +
+				"Unsupported argument\n\nAn argument named \"foo\" is not expected here.\n",
+		},
+		{
+			name: "Apply error found by reading the logs in reverse",
+			run: &sdktypes.Run{
+				WorkspacePath: "group/workspace",
+				ModuleSource:  ptr.String("registry.example.com/group/module/aws"),
+				Plan:          &sdktypes.Plan{Status: sdktypes.PlanFinished},
+				Apply:         &sdktypes.Apply{Status: sdktypes.ApplyErrored, CurrentJobID: ptr.String("job-2")},
+			},
+			logs: longLogs,
+			wantSummary: "Failed to plan module registry.example.com/group/module/aws in workspace group/workspace\n" +
+				"Invalid provider configuration\n\nThe provider needs a region.\n" + strings.TrimPrefix(longLogs,
+				"Initializing modules...\nError: Invalid provider configuration\n\nThe provider needs a region.\n"),
+		},
+		{
+			name: "Errored plan without a job",
+			run: &sdktypes.Run{
+				Plan: &sdktypes.Plan{Status: sdktypes.PlanErrored},
+			},
+			wantWarning: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &applyModuleResource{client: &applyModuleClient{Job: &mockJobService{
+				getJob: func(input *sdktypes.GetJobInput) (*sdktypes.Job, error) {
+					return &sdktypes.Job{
+						Metadata: sdktypes.ResourceMetadata{ID: input.ID},
+						Type:     sdktypes.JobType("plan"),
+						LogSize:  len(tt.logs),
+					}, nil
+				},
+				getJobLogs: newMockJobLogs(tt.logs),
+			}}}
+
+			diags := r.extractRunError(context.Background(), defaultRetryPolicy(), tt.run)
+			if tt.wantWarning {
+				if diags.HasError() || diags.WarningsCount() != 1 {
+					t.Fatalf("extractRunError() = %v, want a single warning", diags)
+				}
+				return
+			}
+			if diags.ErrorsCount() != 1 {
+				t.Fatalf("extractRunError() = %v, want a single error", diags)
+			}
+			if got := diags.Errors()[0].Summary(); got != tt.wantSummary {
+				t.Errorf("extractRunError() summary = %q, want %q", got, tt.wantSummary)
+			}
+		})
+	}
+}
+
+func Test_verifyModuleDigest(t *testing.T) {
+	digest := strings.Repeat("ab", 32)
+	tests := []struct {
+		name     string
+		digest   *string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Matching digest with a prefix",
+			digest:   ptr.String(digest),
+			expected: "sha256:" + strings.ToUpper(digest),
+		},
+		{
+			name:     "Different digest",
+			digest:   ptr.String(strings.Repeat("cd", 32)),
+			expected: digest,
+			wantErr:  true,
+		},
+		{
+			name:     "Module without a digest",
+			expected: digest,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			run := &sdktypes.Run{
+				Metadata:      sdktypes.ResourceMetadata{ID: "run-1"},
+				ModuleSource:  ptr.String("tharsis.example.com/group/module/aws"),
+				ModuleVersion: ptr.String("1.0.0"),
+				ModuleDigest:  tt.digest,
+			}
+			if err := verifyModuleDigest(run, tt.expected); (err != nil) != tt.wantErr {
+				t.Errorf("verifyModuleDigest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_createRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		planStatus  sdktypes.PlanStatus
+		planLogs    string
+		wantStatus  string
+		wantErr     string
+		wantApplies int
+	}{
+		{
+			name:        "Plans and applies a module",
+			planStatus:  sdktypes.PlanFinished,
+			wantStatus:  string(sdktypes.RunApplied),
+			wantApplies: 1,
+		},
+		{
+			name:       "Reports the error from a failed plan",
+			planStatus: sdktypes.PlanErrored,
+			planLogs:   "Initializing modules...\nError: Module not found\n",
+			wantErr:    "Failed to plan module registry.example.com/group/module/aws in workspace group/workspace\nModule not found\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *sdktypes.CreateRunInput
+			applies := 0
+
+			run := &sdktypes.Run{
+				Metadata:      sdktypes.ResourceMetadata{ID: "run-1"},
+				WorkspacePath: "group/workspace",
+				ModuleSource:  ptr.String("registry.example.com/group/module/aws"),
+				ModuleVersion: ptr.String("1.0.0"),
+				Status:        sdktypes.RunPending,
+				Plan:          &sdktypes.Plan{Status: sdktypes.PlanQueued, CurrentJobID: ptr.String("plan-job")},
+			}
+
+			r := &applyModuleResource{client: &applyModuleClient{
+				Run: &mockRunService{
+					createRun: func(input *sdktypes.CreateRunInput) (*sdktypes.Run, error) {
+						created = input
+						return run, nil
+					},
+					getRun: func(_ *sdktypes.GetRunInput) (*sdktypes.Run, error) {
+						if run.Apply == nil {
+							// The plan job has finished.
+							run.Plan.Status = tt.planStatus
+							run.Plan.ResourceAdditions = 2
+							run.Status = sdktypes.RunPlanned
+							if tt.planStatus == sdktypes.PlanErrored {
+								run.Status = sdktypes.RunErrored
+							}
+						} else {
+							// The apply job has finished.
+							run.Apply.Status = sdktypes.ApplyFinished
+							run.Status = sdktypes.RunApplied
+						}
+						return run, nil
+					},
+					getRunVariables: func(_ *sdktypes.GetRunInput) ([]sdktypes.RunVariable, error) {
+						return []sdktypes.RunVariable{}, nil
+					},
+					applyRun: func(input *sdktypes.ApplyRunInput) (*sdktypes.Run, error) {
+						applies++
+						run.Apply = &sdktypes.Apply{Status: sdktypes.ApplyQueued, CurrentJobID: ptr.String("apply-job")}
+						return run, nil
+					},
+				},
+				Job: &mockJobService{
+					getJob: func(input *sdktypes.GetJobInput) (*sdktypes.Job, error) {
+						return &sdktypes.Job{
+							Metadata: sdktypes.ResourceMetadata{ID: input.ID},
+							Type:     sdktypes.JobType("plan"),
+							Status:   "finished",
+							LogSize:  len(tt.planLogs),
+						}, nil
+					},
+					getJobLogs: newMockJobLogs(tt.planLogs),
+				},
+			}}
+
+			output, diags := r.createRun(context.Background(), &createRunInput{
+				model: &ApplyModuleModel{
+					WorkspacePath:        types.StringValue("group/workspace"),
+					ModuleSource:         types.StringValue("registry.example.com/group/module/aws"),
+					ModuleVersion:        types.StringValue("1.0.0"),
+					TargetAddresses:      types.ListNull(types.StringType),
+					EnvironmentVariables: types.MapNull(types.StringType),
+					AutoApprove:          types.BoolValue(true),
+				},
+				pollInterval: time.Millisecond,
+			})
+
+			if created == nil || created.WorkspacePath != "group/workspace" || ptr.ToString(created.ModuleVersion) != "1.0.0" {
+				t.Fatalf("createRun() created run with %+v", created)
+			}
+			if applies != tt.wantApplies {
+				t.Errorf("createRun() applied %d times, want %d", applies, tt.wantApplies)
+			}
+
+			if tt.wantErr != "" {
+				if diags.ErrorsCount() != 1 || diags.Errors()[0].Summary() != tt.wantErr {
+					t.Fatalf("createRun() diags = %v, want error %q", diags, tt.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("createRun() unexpected diags: %v", diags)
+			}
+			if output.runID != "run-1" || output.status != tt.wantStatus || output.moduleVersion != "1.0.0" ||
+				output.resourceAdditions != 2 {
+				t.Errorf("createRun() = %+v", output)
+			}
+		})
+	}
+}
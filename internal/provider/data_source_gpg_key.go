@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// GPGKeyDataSourceData represents a GPG key in Tharsis.
+type GPGKeyDataSourceData struct {
+	GroupPath    types.String `tfsdk:"group_path"`
+	Fingerprint  types.String `tfsdk:"fingerprint"`
+	ID           types.String `tfsdk:"id"`
+	ASCIIArmor   types.String `tfsdk:"ascii_armor"`
+	GPGKeyID     types.String `tfsdk:"gpg_key_id"`
+	ResourcePath types.String `tfsdk:"resource_path"`
+	CreatedBy    types.String `tfsdk:"created_by"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = gpgKeyDataSource{}
+)
+
+type gpgKeyDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t gpgKeyDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_gpg_key"
+}
+
+func (t gpgKeyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis GPG Key data source is used to find an existing GPG key by its group and fingerprint."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"group_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the group the GPG key was added to.",
+				Description:         "Path of the group the GPG key was added to.",
+				Required:            true,
+			},
+			"fingerprint": schema.StringAttribute{
+				MarkdownDescription: "The fingerprint of the GPG key. Spaces and case are ignored.",
+				Description:         "The fingerprint of the GPG key. Spaces and case are ignored.",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the GPG key.",
+				Description:         "String identifier of the GPG key.",
+				Computed:            true,
+			},
+			"ascii_armor": schema.StringAttribute{
+				MarkdownDescription: "The ASCII armored key.",
+				Description:         "The ASCII armored key.",
+				Computed:            true,
+			},
+			"gpg_key_id": schema.StringAttribute{
+				MarkdownDescription: "The GPG key ID, the last 16 characters of the fingerprint.",
+				Description:         "The GPG key ID, the last 16 characters of the fingerprint.",
+				Computed:            true,
+			},
+			"resource_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the GPG key.",
+				Description:         "Path of the GPG key.",
+				Computed:            true,
+			},
+			"created_by": schema.StringAttribute{
+				MarkdownDescription: "The email address of the user or account that created the GPG key.",
+				Description:         "The email address of the user or account that created the GPG key.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (t gpgKeyDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data GPGKeyDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A GPG key's resource path is its group path and fingerprint, which Tharsis resolves as a TRN.
+	fingerprint := strings.ToUpper(strings.ReplaceAll(data.Fingerprint.ValueString(), " ", ""))
+	resourcePath := strings.TrimSuffix(data.GroupPath.ValueString(), "/") + "/" + fingerprint
+
+	found, err := t.provider.client.GPGKey.GetGPGKey(ctx, &ttypes.GetGPGKeyInput{
+		ID: "trn:gpg_key:" + resourcePath,
+	})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(
+				"Couldn't find GPG key",
+				fmt.Sprintf("GPG key '%s' could not be found. Either the GPG key doesn't exist or you don't have access.", resourcePath),
+			)
+			return
+		}
+
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving GPG key", err, resourcePath))
+		return
+	}
+
+	data.ID = types.StringValue(found.Metadata.ID)
+	data.ASCIIArmor = types.StringValue(found.ASCIIArmor)
+	data.GPGKeyID = types.StringValue(found.GPGKeyID)
+	data.ResourcePath = types.StringValue(found.ResourcePath)
+	data.CreatedBy = types.StringValue(found.CreatedBy)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// JobLogsDataSourceData represents the logs of a job in Tharsis.
+type JobLogsDataSourceData struct {
+	JobID   types.String `tfsdk:"job_id"`
+	Start   types.Int64  `tfsdk:"start"`
+	Limit   types.Int64  `tfsdk:"limit"`
+	Logs    types.String `tfsdk:"logs"`
+	LogSize types.Int64  `tfsdk:"log_size"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = jobLogsDataSource{}
+)
+
+type jobLogsDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t jobLogsDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_job_logs"
+}
+
+func (t jobLogsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Job Logs data source is used to retrieve the logs of a plan or apply job."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"job_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the job to retrieve logs from.",
+				Description:         "The ID of the job to retrieve logs from.",
+				Required:            true,
+			},
+			"start": schema.Int64Attribute{
+				MarkdownDescription: "The byte offset in the logs to start reading from. Defaults to 0.",
+				Description:         "The byte offset in the logs to start reading from. Defaults to 0.",
+				Optional:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of bytes to read. Defaults to the remainder of the logs.",
+				Description:         "The maximum number of bytes to read. Defaults to the remainder of the logs.",
+				Optional:            true,
+			},
+			"logs": schema.StringAttribute{
+				MarkdownDescription: "The logs of the job within the requested range.",
+				Description:         "The logs of the job within the requested range.",
+				Computed:            true,
+			},
+			"log_size": schema.Int64Attribute{
+				MarkdownDescription: "The total size of the job's logs in bytes.",
+				Description:         "The total size of the job's logs in bytes.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (t jobLogsDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data JobLogsDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Must get the job to know the size of the logs.
+	job, err := t.provider.client.Job.GetJob(ctx, &ttypes.GetJobInput{
+		ID: data.JobID.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving job", err, data.JobID.ValueString()))
+		return
+	}
+
+	if job == nil {
+		resp.Diagnostics.AddError(
+			"Couldn't find job",
+			fmt.Sprintf("Job '%s' could not be found. Either the job doesn't exist or you don't have access.", data.JobID.ValueString()),
+		)
+		return
+	}
+
+	start := data.Start.ValueInt64()
+	if start < 0 || start > int64(job.LogSize) {
+		resp.Diagnostics.AddError(
+			"Invalid start offset",
+			fmt.Sprintf("Start offset %d is outside of the job's logs, which are %d bytes long.", start, job.LogSize),
+		)
+		return
+	}
+
+	limit := int64(job.LogSize) - start
+	if !data.Limit.IsNull() {
+		if data.Limit.ValueInt64() < 0 {
+			resp.Diagnostics.AddError(
+				"Invalid limit",
+				"Limit cannot be negative.",
+			)
+			return
+		}
+		if data.Limit.ValueInt64() < limit {
+			limit = data.Limit.ValueInt64()
+		}
+	}
+
+	logs := ""
+	if limit > 0 {
+		limit32 := int32(limit)
+		output, err := t.provider.client.Job.GetJobLogs(ctx, &ttypes.GetJobLogsInput{
+			JobID: data.JobID.ValueString(),
+			Start: int32(start),
+			Limit: &limit32,
+		})
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving job logs", err, data.JobID.ValueString()))
+			return
+		}
+
+		// Workaround: The API returns one more character than asked for.
+		logs = output.Logs
+		if len(logs) > int(limit) {
+			logs = logs[:limit]
+		}
+	}
+
+	data.Logs = types.StringValue(logs)
+	data.LogSize = types.Int64Value(int64(job.LogSize))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// NamespaceMembershipsDataSourceData represents the memberships of a group or workspace.
+type NamespaceMembershipsDataSourceData struct {
+	NamespacePath types.String `tfsdk:"namespace_path"`
+	Memberships   types.List   `tfsdk:"memberships"`
+}
+
+// NamespaceMembershipsDataSourceMembership is one of the memberships found by the data source.
+// Exactly one of the user, service account and team IDs is set.
+type NamespaceMembershipsDataSourceMembership struct {
+	ID               string  `tfsdk:"id"`
+	UserID           *string `tfsdk:"user_id"`
+	ServiceAccountID *string `tfsdk:"service_account_id"`
+	TeamID           *string `tfsdk:"team_id"`
+	Role             string  `tfsdk:"role"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = namespaceMembershipsDataSource{}
+)
+
+type namespaceMembershipsDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t namespaceMembershipsDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_namespace_memberships"
+}
+
+func (t namespaceMembershipsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Namespace Memberships data source is used to list who has access to a group or workspace, and with which role."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"namespace_path": schema.StringAttribute{
+				MarkdownDescription: "The full path of the group or workspace.",
+				Description:         "The full path of the group or workspace.",
+				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
+			},
+			"memberships": schema.ListNestedAttribute{
+				MarkdownDescription: "The memberships of the group or workspace.",
+				Description:         "The memberships of the group or workspace.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the membership.",
+							Description:         "The ID of the membership.",
+							Computed:            true,
+						},
+						"user_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the user, if the member is a user.",
+							Description:         "The ID of the user, if the member is a user.",
+							Computed:            true,
+						},
+						"service_account_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the service account, if the member is a service account.",
+							Description:         "The ID of the service account, if the member is a service account.",
+							Computed:            true,
+						},
+						"team_id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the team, if the member is a team.",
+							Description:         "The ID of the team, if the member is a team.",
+							Computed:            true,
+						},
+						"role": schema.StringAttribute{
+							MarkdownDescription: "The name of the member's role.",
+							Description:         "The name of the member's role.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (t namespaceMembershipsDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data NamespaceMembershipsDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespacePath := t.provider.paths.resolve(data.NamespacePath.ValueString())
+	memberships, err := t.provider.client.NamespaceMembership.GetMemberships(ctx, &ttypes.GetNamespaceMembershipsInput{
+		NamespacePath: namespacePath,
+	})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving namespace memberships", err, namespacePath))
+		return
+	}
+
+	found := []NamespaceMembershipsDataSourceMembership{}
+	for _, membership := range memberships {
+		found = append(found, NamespaceMembershipsDataSourceMembership{
+			ID:               membership.Metadata.ID,
+			UserID:           membership.UserID,
+			ServiceAccountID: membership.ServiceAccountID,
+			TeamID:           membership.TeamID,
+			Role:             membership.Role,
+		})
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: map[string]attr.Type{
+		"id":                 types.StringType,
+		"user_id":            types.StringType,
+		"service_account_id": types.StringType,
+		"team_id":            types.StringType,
+		"role":               types.StringType,
+	}}, found)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Memberships = list
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// TeamDataSourceData represents a team in Tharsis.
+type TeamDataSourceData struct {
+	Name           types.String `tfsdk:"name"`
+	ID             types.String `tfsdk:"id"`
+	Description    types.String `tfsdk:"description"`
+	SCIMExternalID types.String `tfsdk:"scim_external_id"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = teamDataSource{}
+)
+
+type teamDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t teamDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_team"
+}
+
+func (t teamDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Team data source is used to find an existing team by its name."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the team.",
+				Description:         "The name of the team.",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the team.",
+				Description:         "String identifier of the team.",
+				Computed:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "A description of the team.",
+				Description:         "A description of the team.",
+				Computed:            true,
+			},
+			"scim_external_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the team in the identity provider that provisioned it through SCIM, if any.",
+				Description:         "The ID of the team in the identity provider that provisioned it through SCIM, if any.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (t teamDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data TeamDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Name.ValueString()
+	found, err := t.provider.client.Team.GetTeam(ctx, &ttypes.GetTeamInput{
+		Name: &name,
+	})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(
+				"Couldn't find team",
+				fmt.Sprintf("Team '%s' could not be found. Either the team doesn't exist or you don't have access.", name),
+			)
+			return
+		}
+
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving team", err, name))
+		return
+	}
+
+	data.ID = types.StringValue(found.Metadata.ID)
+	data.Description = types.StringValue(found.Description)
+	data.SCIMExternalID = types.StringValue(found.SCIMExternalID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
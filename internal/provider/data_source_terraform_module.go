@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// TerraformModuleDataSourceData represents a Terraform module in the Tharsis module registry.
+type TerraformModuleDataSourceData struct {
+	RegistryNamespace types.String `tfsdk:"registry_namespace"`
+	Name              types.String `tfsdk:"name"`
+	System            types.String `tfsdk:"system"`
+	ID                types.String `tfsdk:"id"`
+	GroupPath         types.String `tfsdk:"group_path"`
+	ResourcePath      types.String `tfsdk:"resource_path"`
+	RepositoryURL     types.String `tfsdk:"repository_url"`
+	Private           types.Bool   `tfsdk:"private"`
+	LatestVersion     types.String `tfsdk:"latest_version"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = terraformModuleDataSource{}
+)
+
+type terraformModuleDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t terraformModuleDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_terraform_module"
+}
+
+func (t terraformModuleDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Terraform Module data source is used to find a module in the Tharsis module registry " +
+		"by its registry namespace, name and system."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"registry_namespace": schema.StringAttribute{
+				MarkdownDescription: "The registry namespace of the module, which is its top-level group.",
+				Description:         "The registry namespace of the module, which is its top-level group.",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the module.",
+				Description:         "The name of the module.",
+				Required:            true,
+			},
+			"system": schema.StringAttribute{
+				MarkdownDescription: "The target system for the module (e.g. aws, azure, etc.).",
+				Description:         "The target system for the module (e.g. aws, azure, etc.).",
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the module.",
+				Description:         "String identifier of the module.",
+				Computed:            true,
+			},
+			"group_path": schema.StringAttribute{
+				MarkdownDescription: "Full path of the group the module is in.",
+				Description:         "Full path of the group the module is in.",
+				Computed:            true,
+			},
+			"resource_path": schema.StringAttribute{
+				MarkdownDescription: "The path of the group plus the name and system of the module.",
+				Description:         "The path of the group plus the name and system of the module.",
+				Computed:            true,
+			},
+			"repository_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the repository where the module's source code is kept.",
+				Description:         "The URL of the repository where the module's source code is kept.",
+				Computed:            true,
+			},
+			"private": schema.BoolAttribute{
+				MarkdownDescription: "Whether the module is private, visible only within its registry namespace.",
+				Description:         "Whether the module is private, visible only within its registry namespace.",
+				Computed:            true,
+			},
+			"latest_version": schema.StringAttribute{
+				MarkdownDescription: "The latest version of the module, or null if no version has been published.",
+				Description:         "The latest version of the module, or null if no version has been published.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (t terraformModuleDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data TerraformModuleDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The registry path is the same as in a module source, without the host.
+	registryPath := fmt.Sprintf("%s/%s/%s",
+		data.RegistryNamespace.ValueString(), data.Name.ValueString(), data.System.ValueString())
+
+	found, err := t.provider.client.TerraformModule.GetModule(ctx, &ttypes.GetTerraformModuleInput{
+		Path: &registryPath,
+	})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(
+				"Couldn't find Terraform module",
+				fmt.Sprintf("Terraform module '%s' could not be found. Either the module doesn't exist or you don't have access.", registryPath),
+			)
+			return
+		}
+
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving Terraform module", err, registryPath))
+		return
+	}
+
+	data.ID = types.StringValue(found.Metadata.ID)
+	data.GroupPath = types.StringValue(found.GroupPath)
+	data.ResourcePath = types.StringValue(found.ResourcePath)
+	data.RepositoryURL = types.StringValue(found.RepositoryURL)
+	data.Private = types.BoolValue(found.Private)
+
+	data.LatestVersion, err = latestModuleVersion(ctx, t.provider.client, *found)
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving latest Terraform module version", err, registryPath))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// terraformModulesPageSize is the number of modules to request per page.
+const terraformModulesPageSize = 100
+
+// TerraformModulesDataSourceData represents the Terraform modules found in the Tharsis module registry.
+type TerraformModulesDataSourceData struct {
+	Search    types.String `tfsdk:"search"`
+	GroupPath types.String `tfsdk:"group_path"`
+	Private   types.Bool   `tfsdk:"private"`
+	Modules   types.List   `tfsdk:"modules"`
+}
+
+// TerraformModulesDataSourceModule is one of the modules found by the data source.
+type TerraformModulesDataSourceModule struct {
+	ID                string `tfsdk:"id"`
+	Name              string `tfsdk:"name"`
+	System            string `tfsdk:"system"`
+	RegistryNamespace string `tfsdk:"registry_namespace"`
+	GroupPath         string `tfsdk:"group_path"`
+	ResourcePath      string `tfsdk:"resource_path"`
+	RepositoryURL     string `tfsdk:"repository_url"`
+	Private           bool   `tfsdk:"private"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = terraformModulesDataSource{}
+)
+
+type terraformModulesDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t terraformModulesDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_terraform_modules"
+}
+
+func (t terraformModulesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Terraform Modules data source is used to search the Tharsis module registry."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"search": schema.StringAttribute{
+				MarkdownDescription: "Text to search for in the names of the modules.",
+				Description:         "Text to search for in the names of the modules.",
+				Optional:            true,
+			},
+			"group_path": schema.StringAttribute{
+				MarkdownDescription: "The full path of a group to find modules in, including its subgroups.",
+				Description:         "The full path of a group to find modules in, including its subgroups.",
+				Optional:            true,
+			},
+			"private": schema.BoolAttribute{
+				MarkdownDescription: "If set, only private modules are found if true, or only public modules if false.",
+				Description:         "If set, only private modules are found if true, or only public modules if false.",
+				Optional:            true,
+			},
+			"modules": schema.ListNestedAttribute{
+				MarkdownDescription: "The modules that were found.",
+				Description:         "The modules that were found.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "String identifier of the module.",
+							Description:         "String identifier of the module.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the module.",
+							Description:         "The name of the module.",
+							Computed:            true,
+						},
+						"system": schema.StringAttribute{
+							MarkdownDescription: "The target system for the module (e.g. aws, azure, etc.).",
+							Description:         "The target system for the module (e.g. aws, azure, etc.).",
+							Computed:            true,
+						},
+						"registry_namespace": schema.StringAttribute{
+							MarkdownDescription: "The registry namespace of the module, which is its top-level group.",
+							Description:         "The registry namespace of the module, which is its top-level group.",
+							Computed:            true,
+						},
+						"group_path": schema.StringAttribute{
+							MarkdownDescription: "Full path of the group the module is in.",
+							Description:         "Full path of the group the module is in.",
+							Computed:            true,
+						},
+						"resource_path": schema.StringAttribute{
+							MarkdownDescription: "The path of the group plus the name and system of the module.",
+							Description:         "The path of the group plus the name and system of the module.",
+							Computed:            true,
+						},
+						"repository_url": schema.StringAttribute{
+							MarkdownDescription: "The URL of the repository where the module's source code is kept.",
+							Description:         "The URL of the repository where the module's source code is kept.",
+							Computed:            true,
+						},
+						"private": schema.BoolAttribute{
+							MarkdownDescription: "Whether the module is private, visible only within its registry namespace.",
+							Description:         "Whether the module is private, visible only within its registry namespace.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (t terraformModulesDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data TerraformModulesDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filter *ttypes.TerraformModuleFilter
+	if !data.Search.IsNull() {
+		filter = &ttypes.TerraformModuleFilter{
+			Search: data.Search.ValueStringPointer(),
+		}
+	}
+
+	// Page through all modules, keeping those in the group and with the wanted visibility.
+	found := []TerraformModulesDataSourceModule{}
+	var cursor *string
+	for {
+		limit := int32(terraformModulesPageSize)
+		output, err := t.provider.client.TerraformModule.GetModules(ctx, &ttypes.GetTerraformModulesInput{
+			Filter: filter,
+			PaginationOptions: &ttypes.PaginationOptions{
+				Limit:  &limit,
+				Cursor: cursor,
+			},
+		})
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving Terraform modules", err, data.GroupPath.ValueString()))
+			return
+		}
+
+		for _, module := range output.TerraformModules {
+			if !data.GroupPath.IsNull() && !inGroup(module.GroupPath, data.GroupPath.ValueString()) {
+				continue
+			}
+			if !data.Private.IsNull() && (module.Private != data.Private.ValueBool()) {
+				continue
+			}
+
+			found = append(found, TerraformModulesDataSourceModule{
+				ID:                module.Metadata.ID,
+				Name:              module.Name,
+				System:            module.System,
+				RegistryNamespace: module.RegistryNamespace,
+				GroupPath:         module.GroupPath,
+				ResourcePath:      module.ResourcePath,
+				RepositoryURL:     module.RepositoryURL,
+				Private:           module.Private,
+			})
+		}
+
+		if output.PageInfo == nil || !output.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &output.PageInfo.Cursor
+	}
+
+	modules, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: map[string]attr.Type{
+		"id":                 types.StringType,
+		"name":               types.StringType,
+		"system":             types.StringType,
+		"registry_namespace": types.StringType,
+		"group_path":         types.StringType,
+		"resource_path":      types.StringType,
+		"repository_url":     types.StringType,
+		"private":            types.BoolType,
+	}}, found)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Modules = modules
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// inGroup returns true if a namespace path is the group or one of its subgroups.
+func inGroup(namespacePath, groupPath string) bool {
+	groupPath = strings.TrimSuffix(groupPath, "/")
+	return (namespacePath == groupPath) || strings.HasPrefix(namespacePath, groupPath+"/")
+}
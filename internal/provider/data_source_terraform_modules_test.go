@@ -0,0 +1,50 @@
+package provider
+
+import "testing"
+
+func Test_inGroup(t *testing.T) {
+	tests := []struct {
+		name          string
+		namespacePath string
+		groupPath     string
+		want          bool
+	}{
+		{
+			name:          "Same group",
+			namespacePath: "top/sub",
+			groupPath:     "top/sub",
+			want:          true,
+		},
+		{
+			name:          "Subgroup",
+			namespacePath: "top/sub/deeper",
+			groupPath:     "top",
+			want:          true,
+		},
+		{
+			name:          "Trailing slash is ignored",
+			namespacePath: "top/sub",
+			groupPath:     "top/",
+			want:          true,
+		},
+		{
+			name:          "Group with the same prefix is not a subgroup",
+			namespacePath: "top-other/sub",
+			groupPath:     "top",
+			want:          false,
+		},
+		{
+			name:          "Parent group is not in a subgroup",
+			namespacePath: "top",
+			groupPath:     "top/sub",
+			want:          false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inGroup(tt.namespacePath, tt.groupPath); got != tt.want {
+				t.Errorf("inGroup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
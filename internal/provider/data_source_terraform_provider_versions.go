@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// TerraformProviderVersionsDataSourceData represents the published versions of a provider in the Tharsis provider registry.
+type TerraformProviderVersionsDataSourceData struct {
+	RegistryNamespace types.String `tfsdk:"registry_namespace"`
+	Name              types.String `tfsdk:"name"`
+	Version           types.String `tfsdk:"version"`
+	Versions          types.List   `tfsdk:"versions"`
+}
+
+// TerraformProviderVersionsDataSourceVersion is one of the versions found by the data source.
+type TerraformProviderVersionsDataSourceVersion struct {
+	Version   string                                        `tfsdk:"version"`
+	Protocols []string                                      `tfsdk:"protocols"`
+	Platforms []TerraformProviderVersionsDataSourcePlatform `tfsdk:"platforms"`
+}
+
+// TerraformProviderVersionsDataSourcePlatform is a platform that a provider version was built for.
+type TerraformProviderVersionsDataSourcePlatform struct {
+	OS       string `tfsdk:"os"`
+	Arch     string `tfsdk:"arch"`
+	Filename string `tfsdk:"filename"`
+	SHASum   string `tfsdk:"shasum"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = terraformProviderVersionsDataSource{}
+)
+
+type terraformProviderVersionsDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t terraformProviderVersionsDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_terraform_provider_versions"
+}
+
+func (t terraformProviderVersionsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Terraform Provider Versions data source is used to list the published versions " +
+		"of a provider in the Tharsis provider registry, with their platforms and checksums."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"registry_namespace": schema.StringAttribute{
+				MarkdownDescription: "The registry namespace of the provider, which is its top-level group.",
+				Description:         "The registry namespace of the provider, which is its top-level group.",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the provider.",
+				Description:         "The name of the provider.",
+				Required:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "A version to find, rather than all of them. Each version takes a request per platform, " +
+					"so this is faster for providers with many versions.",
+				Description: "A version to find, rather than all of them. Each version takes a request per platform, " +
+					"so this is faster for providers with many versions.",
+				Optional: true,
+			},
+			"versions": schema.ListNestedAttribute{
+				MarkdownDescription: "The versions that were found.",
+				Description:         "The versions that were found.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"version": schema.StringAttribute{
+							MarkdownDescription: "The semantic version.",
+							Description:         "The semantic version.",
+							Computed:            true,
+						},
+						"protocols": schema.ListAttribute{
+							ElementType:         types.StringType,
+							MarkdownDescription: "The Terraform plugin protocol versions the provider version supports.",
+							Description:         "The Terraform plugin protocol versions the provider version supports.",
+							Computed:            true,
+						},
+						"platforms": schema.ListNestedAttribute{
+							MarkdownDescription: "The platforms the provider version was built for.",
+							Description:         "The platforms the provider version was built for.",
+							Computed:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"os": schema.StringAttribute{
+										MarkdownDescription: "The operating system, such as linux.",
+										Description:         "The operating system, such as linux.",
+										Computed:            true,
+									},
+									"arch": schema.StringAttribute{
+										MarkdownDescription: "The architecture, such as amd64.",
+										Description:         "The architecture, such as amd64.",
+										Computed:            true,
+									},
+									"filename": schema.StringAttribute{
+										MarkdownDescription: "The filename of the package.",
+										Description:         "The filename of the package.",
+										Computed:            true,
+									},
+									"shasum": schema.StringAttribute{
+										MarkdownDescription: "The SHA-256 checksum of the package.",
+										Description:         "The SHA-256 checksum of the package.",
+										Computed:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (t terraformProviderVersionsDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data TerraformProviderVersionsDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := data.RegistryNamespace.ValueString()
+	name := data.Name.ValueString()
+	providerPath := namespace + "/" + name
+
+	versions, err := t.provider.registry.getProviderVersions(ctx, namespace, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error retrieving Terraform provider versions",
+			fmt.Sprintf("Failed to list the versions of provider %s: %v", providerPath, err))
+		return
+	}
+
+	found := []TerraformProviderVersionsDataSourceVersion{}
+	for _, version := range versions {
+		if !data.Version.IsNull() && (version.Version != data.Version.ValueString()) {
+			continue
+		}
+
+		platforms := []TerraformProviderVersionsDataSourcePlatform{}
+		for _, platform := range version.Platforms {
+			pkg, err := t.provider.registry.getProviderPackage(ctx, namespace, name, version.Version, platform)
+			if err != nil {
+				resp.Diagnostics.AddError("Error retrieving Terraform provider package",
+					fmt.Sprintf("Failed to get the %s_%s package of version %s of provider %s: %v",
+						platform.OS, platform.Arch, version.Version, providerPath, err))
+				return
+			}
+
+			platforms = append(platforms, TerraformProviderVersionsDataSourcePlatform{
+				OS:       platform.OS,
+				Arch:     platform.Arch,
+				Filename: pkg.Filename,
+				SHASum:   pkg.SHASum,
+			})
+		}
+
+		protocols := append([]string{}, version.Protocols...)
+		found = append(found, TerraformProviderVersionsDataSourceVersion{
+			Version:   version.Version,
+			Protocols: protocols,
+			Platforms: platforms,
+		})
+	}
+
+	if !data.Version.IsNull() && (len(found) == 0) {
+		resp.Diagnostics.AddError("Couldn't find Terraform provider version",
+			fmt.Sprintf("Version %s of provider %s could not be found.", data.Version.ValueString(), providerPath))
+		return
+	}
+
+	versionsList, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: map[string]attr.Type{
+		"version":   types.StringType,
+		"protocols": types.ListType{ElemType: types.StringType},
+		"platforms": types.ListType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+			"os":       types.StringType,
+			"arch":     types.StringType,
+			"filename": types.StringType,
+			"shasum":   types.StringType,
+		}}},
+	}}, found)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Versions = versionsList
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
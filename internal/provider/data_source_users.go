@@ -0,0 +1,255 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// usersPageSize is the number of users to request per page.
+const usersPageSize = 100
+
+// UsersDataSourceData represents the users found in Tharsis.
+type UsersDataSourceData struct {
+	Search    types.String `tfsdk:"search"`
+	Usernames types.Set    `tfsdk:"usernames"`
+	Users     types.List   `tfsdk:"users"`
+	Missing   types.Set    `tfsdk:"missing"`
+}
+
+// UsersDataSourceUser is one of the users found by the data source.
+type UsersDataSourceUser struct {
+	ID       string `tfsdk:"id"`
+	Username string `tfsdk:"username"`
+	Email    string `tfsdk:"email"`
+	Active   bool   `tfsdk:"active"`
+	Admin    bool   `tfsdk:"admin"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = usersDataSource{}
+)
+
+type usersDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t usersDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_users"
+}
+
+func (t usersDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Users data source is used to look up users by username or email, such as to check that " +
+		"the users allowed by access rules and memberships exist and are active."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"search": schema.StringAttribute{
+				MarkdownDescription: "Find the users whose username or email contains this text. Conflicts with usernames.",
+				Description:         "Find the users whose username or email contains this text. Conflicts with usernames.",
+				Optional:            true,
+			},
+			"usernames": schema.SetAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Find the users with exactly these usernames or emails. Emails are compared without regard to case. " +
+					"Those that aren't found are listed in missing. Conflicts with search.",
+				Description: "Find the users with exactly these usernames or emails. Emails are compared without regard to case. " +
+					"Those that aren't found are listed in missing. Conflicts with search.",
+				Optional: true,
+			},
+			"users": schema.ListNestedAttribute{
+				MarkdownDescription: "The users that were found.",
+				Description:         "The users that were found.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the user.",
+							Description:         "The ID of the user.",
+							Computed:            true,
+						},
+						"username": schema.StringAttribute{
+							MarkdownDescription: "The username of the user.",
+							Description:         "The username of the user.",
+							Computed:            true,
+						},
+						"email": schema.StringAttribute{
+							MarkdownDescription: "The email of the user.",
+							Description:         "The email of the user.",
+							Computed:            true,
+						},
+						"active": schema.BoolAttribute{
+							MarkdownDescription: "Whether the user is active.",
+							Description:         "Whether the user is active.",
+							Computed:            true,
+						},
+						"admin": schema.BoolAttribute{
+							MarkdownDescription: "Whether the user is a system administrator.",
+							Description:         "Whether the user is a system administrator.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"missing": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The usernames or emails in usernames that didn't match any user.",
+				Description:         "The usernames or emails in usernames that didn't match any user.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (t usersDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data UsersDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Search.IsNull() && !data.Usernames.IsNull() {
+		resp.Diagnostics.AddError("Conflicting attributes", "Only one of search and usernames may be set.")
+		return
+	}
+
+	var usernames []string
+	resp.Diagnostics.Append(data.Usernames.ElementsAs(ctx, &usernames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found := []UsersDataSourceUser{}
+	missing := []string{}
+	if data.Usernames.IsNull() {
+		users, err := searchUsers(ctx, t.provider.client, data.Search.ValueStringPointer())
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving users", err, data.Search.ValueString()))
+			return
+		}
+		for _, user := range users {
+			found = append(found, toUsersDataSourceUser(user))
+		}
+	} else {
+		// Search for each username, since the API only matches part of a username or email.
+		// A user found by both its username and email is only listed once.
+		seen := map[string]bool{}
+		for _, username := range usernames {
+			users, err := searchUsers(ctx, t.provider.client, &username)
+			if err != nil {
+				resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving users", err, username))
+				return
+			}
+
+			user := findUser(users, username)
+			if user == nil {
+				missing = append(missing, username)
+				continue
+			}
+			if !seen[user.Metadata.ID] {
+				seen[user.Metadata.ID] = true
+				found = append(found, toUsersDataSourceUser(*user))
+			}
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].Username < found[j].Username
+	})
+
+	users, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: map[string]attr.Type{
+		"id":       types.StringType,
+		"username": types.StringType,
+		"email":    types.StringType,
+		"active":   types.BoolType,
+		"admin":    types.BoolType,
+	}}, found)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Users = users
+
+	data.Missing, diags = types.SetValueFrom(ctx, types.StringType, missing)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// searchUsers pages through the users whose username or email contains search, or all users if it's nil.
+func searchUsers(ctx context.Context, client *tharsis.Client, search *string) ([]ttypes.User, error) {
+	var filter *ttypes.UserFilter
+	if search != nil {
+		filter = &ttypes.UserFilter{Search: search}
+	}
+
+	users := []ttypes.User{}
+	var cursor *string
+	for {
+		limit := int32(usersPageSize)
+		output, err := client.User.GetUsers(ctx, &ttypes.GetUsersInput{
+			Filter: filter,
+			PaginationOptions: &ttypes.PaginationOptions{
+				Limit:  &limit,
+				Cursor: cursor,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, output.Users...)
+
+		if output.PageInfo == nil || !output.PageInfo.HasNextPage {
+			return users, nil
+		}
+		cursor = &output.PageInfo.Cursor
+	}
+}
+
+// findUser returns the user whose username is exactly username, or whose email matches it in any case,
+// or nil if there isn't one.  A username match is preferred.
+func findUser(users []ttypes.User, username string) *ttypes.User {
+	for i := range users {
+		if users[i].Username == username {
+			return &users[i]
+		}
+	}
+
+	for i := range users {
+		if strings.EqualFold(users[i].Email, username) {
+			return &users[i]
+		}
+	}
+
+	return nil
+}
+
+// toUsersDataSourceUser converts a Tharsis user to a user found by the data source.
+func toUsersDataSourceUser(user ttypes.User) UsersDataSourceUser {
+	return UsersDataSourceUser{
+		ID:       user.Metadata.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Active:   user.Active,
+		Admin:    user.Admin,
+	}
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"testing"
+
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+func Test_findUser(t *testing.T) {
+	users := []ttypes.User{
+		{Metadata: ttypes.ResourceMetadata{ID: "user-1"}, Username: "jdoe-admin", Email: "jdoe@example.com"},
+		{Metadata: ttypes.ResourceMetadata{ID: "user-2"}, Username: "jdoe", Email: "john.doe@example.com"},
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		wantID   string
+	}{
+		{
+			name:     "Exact username",
+			username: "jdoe",
+			wantID:   "user-2",
+		},
+		{
+			name:     "Email in any case",
+			username: "JDoe@Example.com",
+			wantID:   "user-1",
+		},
+		{
+			name:     "Part of a username isn't a match",
+			username: "doe",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := findUser(users, tt.username)
+
+			var gotID string
+			if user != nil {
+				gotID = user.Metadata.ID
+			}
+			if gotID != tt.wantID {
+				t.Errorf("findUser() ID = %q, want %q", gotID, tt.wantID)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -19,15 +20,27 @@ import (
 
 const (
 	tharsisGroupPathEnvVar = "THARSIS_GROUP_PATH"
+
+	// defaultOutputsWaitTimeout is how long to wait for a state version or output, if waiting.
+	defaultOutputsWaitTimeout = 10 * time.Minute
+
+	// outputsPollInterval is how often to check the workspace while waiting for a state version or output.
+	outputsPollInterval = 10 * time.Second
 )
 
 // WorkspacesOutputsDataSourceData represents the outputs for a workspace in Tharsis.
 type WorkspacesOutputsDataSourceData struct {
-	Outputs        map[string]string `tfsdk:"outputs"`
-	Path           types.String      `tfsdk:"path"`
-	FullPath       types.String      `tfsdk:"full_path"`
-	WorkspaceID    types.String      `tfsdk:"workspace_id"`
-	StateVersionID types.String      `tfsdk:"state_version_id"`
+	Outputs          map[string]string `tfsdk:"outputs"`
+	SensitiveOutputs map[string]string `tfsdk:"sensitive_outputs"`
+	OutputNames      types.Set         `tfsdk:"output_names"`
+	Path             types.String      `tfsdk:"path"`
+	FullPath         types.String      `tfsdk:"full_path"`
+	WorkspaceID      types.String      `tfsdk:"workspace_id"`
+	StateVersionID   types.String      `tfsdk:"state_version_id"`
+	// WaitForStateVersion, WaitForOutput and WaitTimeout control waiting for the workspace's first run.
+	WaitForStateVersion types.Bool   `tfsdk:"wait_for_state_version"`
+	WaitForOutput       types.String `tfsdk:"wait_for_output"`
+	WaitTimeout         types.String `tfsdk:"wait_timeout"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
@@ -80,6 +93,36 @@ func (t workspaceOutputsDataSource) Schema(_ context.Context, _ datasource.Schem
 				Description:         "The outputs of the workspace specified by the path.",
 				Computed:            true,
 			},
+			"sensitive_outputs": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The outputs of the workspace that are marked sensitive, which are also in outputs.",
+				Description:         "The outputs of the workspace that are marked sensitive, which are also in outputs.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"output_names": schema.SetAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The names of the outputs to retrieve. Defaults to all outputs.",
+				Description:         "The names of the outputs to retrieve. Defaults to all outputs.",
+				Optional:            true,
+			},
+			"wait_for_state_version": schema.BoolAttribute{
+				MarkdownDescription: "Whether to wait for the workspace to have a state version, such as when it's read before " +
+					"the first run of a new workspace has finished. Defaults to false.",
+				Description: "Whether to wait for the workspace to have a state version, such as when it's read before " +
+					"the first run of a new workspace has finished. Defaults to false.",
+				Optional: true,
+			},
+			"wait_for_output": schema.StringAttribute{
+				MarkdownDescription: "The name of an output to wait for. Implies wait_for_state_version.",
+				Description:         "The name of an output to wait for. Implies wait_for_state_version.",
+				Optional:            true,
+			},
+			"wait_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for the state version or output, such as \"30m\". Defaults to 10 minutes.",
+				Description:         "How long to wait for the state version or output, such as \"30m\". Defaults to 10 minutes.",
+				Optional:            true,
+			},
 		},
 	}
 }
@@ -122,77 +165,191 @@ func (t workspaceOutputsDataSource) Read(ctx context.Context,
 		return
 	}
 
+	waitTimeout := defaultOutputsWaitTimeout
+	if !data.WaitTimeout.IsNull() && !data.WaitTimeout.IsUnknown() {
+		waitTimeout, err = time.ParseDuration(data.WaitTimeout.ValueString())
+		if err != nil || waitTimeout <= 0 {
+			resp.Diagnostics.AddError(
+				"Invalid wait_timeout",
+				fmt.Sprintf("wait_timeout must be a positive duration, such as \"30m\": %q", data.WaitTimeout.ValueString()),
+			)
+			return
+		}
+	}
+	waitForOutput := data.WaitForOutput.ValueString()
+	waiting := data.WaitForStateVersion.ValueBool() || (waitForOutput != "")
+
 	// For later dereference, input.Path is known to not be nil.
 	input := &ttypes.GetWorkspaceInput{
 		Path: &path,
 	}
 
-	workspace, err := t.provider.client.Workspaces.GetWorkspace(ctx, input)
-	if err != nil {
+	// Unless waiting, the workspace may have been read by other resources in this refresh.
+	var workspaces workspaceService = t.provider.client.Workspaces
+	if !waiting && (t.provider.workspaces != nil) {
+		workspaces = t.provider.workspaces
+	}
+
+	// Poll until the workspace has a state version and output, unless not waiting.
+	deadline := time.Now().Add(waitTimeout)
+	var workspace *ttypes.Workspace
+	for {
+		workspace, err = workspaces.GetWorkspace(ctx, input)
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving workspace", err, path))
+			return
+		}
+
+		if workspace == nil {
+			resp.Diagnostics.AddError(
+				"Couldn't find workspace",
+				fmt.Sprintf("Workspace '%s' could not be found. Either the workspace doesn't exist or you don't have access.", *input.Path),
+			)
+			return
+		}
+
+		if (workspace.CurrentStateVersion != nil) &&
+			((waitForOutput == "") || hasStateVersionOutput(workspace.CurrentStateVersion, waitForOutput)) {
+			break
+		}
+
+		if !waiting || time.Now().After(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			resp.Diagnostics.AddError(
+				"Stopped waiting for workspace outputs",
+				fmt.Sprintf("Stopped waiting for workspace '%s' to have a current state version: %v", *input.Path, ctx.Err()),
+			)
+			return
+		case <-time.After(outputsPollInterval):
+		}
+	}
+
+	if workspace.CurrentStateVersion == nil {
+		detail := fmt.Sprintf("Workspace '%s' does not have a current state version.", *input.Path)
+		if waiting {
+			detail = fmt.Sprintf("Workspace '%s' still does not have a current state version after %s.", *input.Path, waitTimeout)
+		}
+		resp.Diagnostics.AddError("Workspace doesn't have a current state version", detail)
+		return
+	}
+
+	if (waitForOutput != "") && !hasStateVersionOutput(workspace.CurrentStateVersion, waitForOutput) {
 		resp.Diagnostics.AddError(
-			"Error retrieving workspace",
-			err.Error(),
+			"Workspace doesn't have the output",
+			fmt.Sprintf("The current state version of workspace '%s' still does not have output '%s' after %s.",
+				*input.Path, waitForOutput, waitTimeout),
 		)
 		return
 	}
 
-	if workspace == nil {
+	var outputNames []string
+	resp.Diagnostics.Append(data.OutputNames.ElementsAs(ctx, &outputNames, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	outputs, err := stateVersionOutputs(workspace.CurrentStateVersion, t.isJSONEncoded)
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Couldn't find workspace",
-			fmt.Sprintf("Workspace '%s' could not be found. Either the workspace doesn't exist or you don't have access.", *input.Path),
+			"Failed to parse workspace outputs",
+			err.Error(),
 		)
 		return
 	}
 
-	if workspace.CurrentStateVersion == nil {
+	data.Outputs, data.SensitiveOutputs, err = selectOutputs(workspace.CurrentStateVersion, outputs, outputNames)
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Workspace doesn't have a current state version",
-			fmt.Sprintf("Workspace '%s' does not have a current state version.", *input.Path),
+			"Failed to select workspace outputs",
+			err.Error(),
 		)
 		return
 	}
 
-	data.Outputs = map[string]string{}
-	for _, output := range workspace.CurrentStateVersion.Outputs {
-		if !t.isJSONEncoded {
+	// Add additional attributes
+	data.FullPath = types.StringValue(path)
+	data.WorkspaceID = types.StringValue(workspace.Metadata.ID)
+	data.StateVersionID = types.StringValue(workspace.CurrentStateVersion.Metadata.ID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// hasStateVersionOutput returns true if a state version has an output with the given name.
+func hasStateVersionOutput(stateVersion *ttypes.StateVersion, name string) bool {
+	for _, output := range stateVersion.Outputs {
+		if output.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// selectOutputs keeps only the named outputs, if any names are given, and also returns those marked sensitive.
+// It's an error to name an output the state version doesn't have.
+func selectOutputs(stateVersion *ttypes.StateVersion, outputs map[string]string, names []string,
+) (map[string]string, map[string]string, error) {
+	wanted := map[string]bool{}
+	for _, name := range names {
+		if !hasStateVersionOutput(stateVersion, name) {
+			return nil, nil, fmt.Errorf("output \"%s\" not found in the workspace's current state version", name)
+		}
+		wanted[name] = true
+	}
+
+	selected := map[string]string{}
+	sensitive := map[string]string{}
+	for _, output := range stateVersion.Outputs {
+		value, ok := outputs[output.Name]
+		if !ok || ((len(wanted) > 0) && !wanted[output.Name]) {
+			continue
+		}
+
+		selected[output.Name] = value
+		if output.Sensitive {
+			sensitive[output.Name] = value
+		}
+	}
+
+	return selected, sensitive, nil
+}
+
+// stateVersionOutputs converts the outputs of a state version to strings.
+// Unless jsonEncoded is set, only string outputs are supported and others are skipped.
+func stateVersionOutputs(stateVersion *ttypes.StateVersion, jsonEncoded bool) (map[string]string, error) {
+	result := map[string]string{}
+	for _, output := range stateVersion.Outputs {
+		if !jsonEncoded {
 			switch output.Type {
 			// Currently Strings are only supported
 			case cty.String:
 			default:
-				// Unsupported types for non-json encoded provider need to be skipped
+				// Unsupported types for non-json encoded outputs need to be skipped
 				continue
 			}
 		}
 
 		b, err := ctyjson.Marshal(output.Value, output.Type)
 		if err != nil {
-			resp.Diagnostics.AddError(
-				fmt.Sprintf("Fail to parse value from output \"%s\"", output.Name),
-				err.Error(),
-			)
+			return nil, fmt.Errorf("failed to parse value from output \"%s\": %v", output.Name, err)
 		}
 
-		if !t.isJSONEncoded {
+		if !jsonEncoded {
 			var s string
 			if err := json.Unmarshal(b, &s); err != nil {
-				resp.Diagnostics.AddError(
-					fmt.Sprintf("Failed to parse value from output \"%s\"", output.Name),
-					err.Error(),
-				)
-				return
+				return nil, fmt.Errorf("failed to parse value from output \"%s\": %v", output.Name, err)
 			}
-			data.Outputs[output.Name] = s
+			result[output.Name] = s
 		} else {
-			data.Outputs[output.Name] = string(b)
+			result[output.Name] = string(b)
 		}
 	}
 
-	// Add additional attributes
-	data.FullPath = types.StringValue(path)
-	data.WorkspaceID = types.StringValue(workspace.Metadata.ID)
-	data.StateVersionID = types.StringValue(workspace.CurrentStateVersion.Metadata.ID)
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return result, nil
 }
 
 func resolvePath(path string) (string, error) {
@@ -2,7 +2,10 @@ package provider
 
 import (
 	"os"
+	"reflect"
 	"testing"
+
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
 
 func Test_resolvePath(t *testing.T) {
@@ -124,6 +127,104 @@ func Test_resolvePath(t *testing.T) {
 	}
 }
 
+func Test_hasStateVersionOutput(t *testing.T) {
+	stateVersion := &ttypes.StateVersion{
+		Outputs: []ttypes.StateVersionOutput{
+			{Name: "vpc_id"},
+			{Name: "subnet_ids"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   bool
+	}{
+		{
+			name:   "Output is present",
+			output: "subnet_ids",
+			want:   true,
+		},
+		{
+			name:   "Output is missing",
+			output: "cluster_name",
+			want:   false,
+		},
+		{
+			name:   "Output names are case sensitive",
+			output: "VPC_ID",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasStateVersionOutput(stateVersion, tt.output); got != tt.want {
+				t.Errorf("hasStateVersionOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_selectOutputs(t *testing.T) {
+	stateVersion := &ttypes.StateVersion{
+		Outputs: []ttypes.StateVersionOutput{
+			{Name: "vpc_id"},
+			{Name: "db_password", Sensitive: true},
+			{Name: "subnet_ids"},
+		},
+	}
+	// subnet_ids is missing, as it would be if it weren't a string.
+	outputs := map[string]string{
+		"vpc_id":      "vpc-123",
+		"db_password": "secret",
+	}
+
+	tests := []struct {
+		name          string
+		names         []string
+		wantOutputs   map[string]string
+		wantSensitive map[string]string
+		wantErr       bool
+	}{
+		{
+			name:          "All outputs, with sensitive ones also listed separately",
+			wantOutputs:   map[string]string{"vpc_id": "vpc-123", "db_password": "secret"},
+			wantSensitive: map[string]string{"db_password": "secret"},
+		},
+		{
+			name:          "Only the named outputs",
+			names:         []string{"db_password"},
+			wantOutputs:   map[string]string{"db_password": "secret"},
+			wantSensitive: map[string]string{"db_password": "secret"},
+		},
+		{
+			name:          "Named output that isn't a string is skipped",
+			names:         []string{"vpc_id", "subnet_ids"},
+			wantOutputs:   map[string]string{"vpc_id": "vpc-123"},
+			wantSensitive: map[string]string{},
+		},
+		{
+			name:    "Named output that doesn't exist",
+			names:   []string{"cluster_name"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, sensitive, err := selectOutputs(stateVersion, outputs, tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectOutputs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(selected, tt.wantOutputs) && !tt.wantErr {
+				t.Errorf("selectOutputs() outputs = %v, want %v", selected, tt.wantOutputs)
+			}
+			if !reflect.DeepEqual(sensitive, tt.wantSensitive) && !tt.wantErr {
+				t.Errorf("selectOutputs() sensitive = %v, want %v", sensitive, tt.wantSensitive)
+			}
+		})
+	}
+}
+
 func strPtr(str string) *string {
 	return &str
 }
@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// workspacesPageSize is the number of workspaces to request per page.
+const workspacesPageSize = 100
+
+// WorkspacesDataSourceData represents the workspaces found in Tharsis.
+type WorkspacesDataSourceData struct {
+	GroupPath  types.String `tfsdk:"group_path"`
+	Workspaces types.List   `tfsdk:"workspaces"`
+}
+
+// WorkspacesDataSourceWorkspace is one of the workspaces found by the data source.
+type WorkspacesDataSourceWorkspace struct {
+	ID       string `tfsdk:"id"`
+	Name     string `tfsdk:"name"`
+	FullPath string `tfsdk:"full_path"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource = workspacesDataSource{}
+)
+
+type workspacesDataSource struct {
+	provider tharsisProvider
+}
+
+// Metadata returns the full name of the data source.
+func (t workspacesDataSource) Metadata(_ context.Context,
+	_ datasource.MetadataRequest, resp *datasource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_workspaces"
+}
+
+func (t workspacesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	description := "Tharsis Workspaces data source is used to find workspaces by group."
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"group_path": schema.StringAttribute{
+				MarkdownDescription: "The full path of a group to find workspaces in, including its subgroups.",
+				Description:         "The full path of a group to find workspaces in, including its subgroups.",
+				Optional:            true,
+			},
+			"workspaces": schema.ListNestedAttribute{
+				MarkdownDescription: "The workspaces that were found.",
+				Description:         "The workspaces that were found.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The ID of the workspace.",
+							Description:         "The ID of the workspace.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the workspace.",
+							Description:         "The name of the workspace.",
+							Computed:            true,
+						},
+						"full_path": schema.StringAttribute{
+							MarkdownDescription: "The full path of the workspace.",
+							Description:         "The full path of the workspace.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (t workspacesDataSource) Read(ctx context.Context,
+	req datasource.ReadRequest, resp *datasource.ReadResponse,
+) {
+	var data WorkspacesDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filter *ttypes.WorkspaceFilter
+	if !data.GroupPath.IsNull() {
+		filter = &ttypes.WorkspaceFilter{
+			GroupPath: data.GroupPath.ValueStringPointer(),
+		}
+	}
+
+	// Page through all workspaces.
+	found := []WorkspacesDataSourceWorkspace{}
+	var cursor *string
+	for {
+		limit := int32(workspacesPageSize)
+		output, err := t.provider.client.Workspaces.GetWorkspaces(ctx, &ttypes.GetWorkspacesInput{
+			Filter: filter,
+			PaginationOptions: &ttypes.PaginationOptions{
+				Limit:  &limit,
+				Cursor: cursor,
+			},
+		})
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error retrieving workspaces", err, data.GroupPath.ValueString()))
+			return
+		}
+
+		for _, workspace := range output.Workspaces {
+			found = append(found, WorkspacesDataSourceWorkspace{
+				ID:       workspace.Metadata.ID,
+				Name:     workspace.Name,
+				FullPath: workspace.FullPath,
+			})
+		}
+
+		if output.PageInfo == nil || !output.PageInfo.HasNextPage {
+			break
+		}
+		cursor = &output.PageInfo.Cursor
+	}
+
+	workspaces, diags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: map[string]attr.Type{
+		"id":        types.StringType,
+		"name":      types.StringType,
+		"full_path": types.StringType,
+	}}, found)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.Workspaces = workspaces
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
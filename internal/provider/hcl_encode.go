@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// hclIdentifierPattern matches object keys that don't need to be quoted in HCL.
+var hclIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// jsonToHCL converts a JSON document, such as the output of jsonencode, to the equivalent HCL expression.
+// Strings are escaped so Tharsis doesn't treat "${" or "%{" in them as template sequences.
+func jsonToHCL(document string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	if decoder.More() {
+		return "", fmt.Errorf("failed to parse JSON: unexpected data after the value")
+	}
+
+	var buf bytes.Buffer
+	writeHCLValue(&buf, value, "")
+
+	return buf.String(), nil
+}
+
+// writeHCLValue writes a value decoded from JSON as an HCL expression.
+func writeHCLValue(buf *bytes.Buffer, value any, indent string) {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		fmt.Fprintf(buf, "%t", v)
+	case json.Number:
+		buf.WriteString(v.String())
+	case string:
+		buf.WriteString(hclQuote(v))
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+
+		buf.WriteString("[\n")
+		for _, element := range v {
+			buf.WriteString(indent + "  ")
+			writeHCLValue(buf, element, indent+"  ")
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(indent + "]")
+	case map[string]any:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("{\n")
+		for _, key := range keys {
+			buf.WriteString(indent + "  ")
+			if hclIdentifierPattern.MatchString(key) {
+				buf.WriteString(key)
+			} else {
+				buf.WriteString(hclQuote(key))
+			}
+			buf.WriteString(" = ")
+			writeHCLValue(buf, v[key], indent+"  ")
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + "}")
+	}
+}
+
+// hclQuote returns a string as a quoted HCL string literal.
+func hclQuote(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+
+	for i, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '$', '%':
+			// Double the character to escape a template sequence.
+			if strings.HasPrefix(s[i+1:], "{") {
+				buf.WriteRune(r)
+			}
+			buf.WriteRune(r)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+
+	buf.WriteByte('"')
+	return buf.String()
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"testing"
+)
+
+func Test_jsonToHCL(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Primitive values are unchanged",
+			document: `12.5`,
+			want:     `12.5`,
+		},
+		{
+			name:     "Template sequences in strings are escaped",
+			document: `"${var.a} %{if x} $5 100%"`,
+			want:     `"$${var.a} %%{if x} $5 100%"`,
+		},
+		{
+			name:     "Objects are sorted and keys are quoted only when needed",
+			document: `{"zone":"a","Name":"web","tag key":null,"ports":[80,443],"empty":{},"none":[]}`,
+			want: `{
+  Name = "web"
+  empty = {}
+  none = []
+  ports = [
+    80,
+    443,
+  ]
+  "tag key" = null
+  zone = "a"
+}`,
+		},
+		{
+			name:     "Nested objects are indented",
+			document: `[{"enabled":true,"path":"C:\\tmp\n"}]`,
+			want: `[
+  {
+    enabled = true
+    path = "C:\\tmp\n"
+  },
+]`,
+		},
+		{
+			name:     "Invalid JSON is an error",
+			document: `{"a":`,
+			wantErr:  true,
+		},
+		{
+			name:     "Trailing data is an error",
+			document: `{} {}`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonToHCL(tt.document)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jsonToHCL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("jsonToHCL() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+)
+
+// importByResourcePath imports a resource by its ID or its resource path, such as group/sub-group/name.
+// The get function looks up the resource by ID, or by a Tharsis Resource Name (TRN), and returns its ID.
+func importByResourcePath(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse,
+	resourceType, description string, get func(id string) (string, error),
+) {
+	id, err := get(resourceIDForImport(resourceType, req.ID))
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(
+				"Import "+description+" not found: "+req.ID,
+				"",
+			)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Import "+description+" not found: "+req.ID,
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// resourceIDForImport returns the ID to look up a resource being imported.
+// An import ID containing a slash is a resource path, which Tharsis resolves when it's given
+// as a TRN.  Anything else is passed through unchanged.
+func resourceIDForImport(resourceType, importID string) string {
+	if strings.HasPrefix(importID, "trn:") || !strings.Contains(importID, "/") {
+		return importID
+	}
+
+	return "trn:" + resourceType + ":" + importID
+}
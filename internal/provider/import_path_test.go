@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"testing"
+)
+
+func Test_resourceIDForImport(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		importID     string
+		want         string
+	}{
+		{
+			name:         "An ID is passed through",
+			resourceType: "service_account",
+			importID:     "d2c4c5f8-98b2-4b4c-a0c4-4d6c1c2e5a1b",
+			want:         "d2c4c5f8-98b2-4b4c-a0c4-4d6c1c2e5a1b",
+		},
+		{
+			name:         "A resource path is converted to a TRN",
+			resourceType: "service_account",
+			importID:     "top-group/sub-group/deployer",
+			want:         "trn:service_account:top-group/sub-group/deployer",
+		},
+		{
+			name:         "A TRN is passed through",
+			resourceType: "service_account",
+			importID:     "trn:service_account:top-group/deployer",
+			want:         "trn:service_account:top-group/deployer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resourceIDForImport(tt.resourceType, tt.importID); got != tt.want {
+				t.Errorf("resourceIDForImport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// logDiagnostic is a diagnostic reported by Terraform in a machine-readable (-json) log.
+type logDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail"`
+	Address  string `json:"address"`
+}
+
+// jsonLogLine is the part of a line of a machine-readable Terraform log that is of interest.
+type jsonLogLine struct {
+	Type       string         `json:"type"`
+	Diagnostic *logDiagnostic `json:"diagnostic"`
+}
+
+// parseJSONLogDiagnostics returns the diagnostics found in a machine-readable Terraform log, in order.
+// Lines that aren't JSON, such as those added by the job executor, are skipped, as are any
+// partial lines at the start of logs that were read from the middle.
+func parseJSONLogDiagnostics(logs string) []logDiagnostic {
+	result := []logDiagnostic{}
+
+	for _, line := range strings.Split(logs, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		var parsed jsonLogLine
+		if err := json.Unmarshal([]byte(line), &parsed); err != nil {
+			continue
+		}
+
+		if parsed.Type == "diagnostic" && parsed.Diagnostic != nil {
+			result = append(result, *parsed.Diagnostic)
+		}
+	}
+
+	return result
+}
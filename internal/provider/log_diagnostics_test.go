@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseJSONLogDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		logs string
+		want []logDiagnostic
+	}{
+		{
+			name: "Plain text logs have no diagnostics",
+			logs: "Initializing the backend...\n\nError: Unsupported argument\n",
+			want: []logDiagnostic{},
+		},
+		{
+			name: "Each diagnostic is returned separately with its address",
+			logs: `{"@level":"info","@message":"Terraform 1.5.7","type":"version"}
+{"@level":"error","@message":"Error: Invalid value","diagnostic":{"severity":"error","summary":"Invalid value","detail":"Must be positive.","address":"aws_instance.a"},"type":"diagnostic"}
+{"@level":"warn","@message":"Warning: Deprecated","diagnostic":{"severity":"warning","summary":"Deprecated","detail":""},"type":"diagnostic"}
+`,
+			want: []logDiagnostic{
+				{Severity: "error", Summary: "Invalid value", Detail: "Must be positive.", Address: "aws_instance.a"},
+				{Severity: "warning", Summary: "Deprecated"},
+			},
+		},
+		{
+			name: "Partial and non-JSON lines are skipped",
+			logs: `severity":"error"},"type":"diagnostic"}
+job executor: starting plan
+{"@level":"error","diagnostic":{"severity":"error","summary":"Missing provider"},"type":"diagnostic"}`,
+			want: []logDiagnostic{
+				{Severity: "error", Summary: "Missing provider"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseJSONLogDiagnostics(tt.logs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSONLogDiagnostics() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// ManagedIdentityInlineAccessRuleModel is an access rule managed along with its managed identity.
+// Empty sets and lists are left null, so a rule read back from Tharsis matches one that omits them.
+type ManagedIdentityInlineAccessRuleModel struct {
+	Type                      types.String `tfsdk:"type"`
+	RunStage                  types.String `tfsdk:"run_stage"`
+	AllowedUsers              types.Set    `tfsdk:"allowed_users"`
+	AllowedServiceAccounts    types.Set    `tfsdk:"allowed_service_accounts"`
+	AllowedTeams              types.Set    `tfsdk:"allowed_teams"`
+	VerifyStateLineage        types.Bool   `tfsdk:"verify_state_lineage"`
+	ModuleAttestationPolicies types.List   `tfsdk:"module_attestation_policies"`
+}
+
+// moduleAttestationPolicyType is the type of a module attestation policy in an access rule.
+var moduleAttestationPolicyType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"predicate_type": types.StringType,
+	"public_key":     types.StringType,
+}}
+
+// inlineAccessRulesAttribute returns the schema attribute for the access rules of a managed identity.
+func inlineAccessRulesAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		MarkdownDescription: "Access rules of the managed identity. If set, rules not in the list are removed, " +
+			"so don't combine it with tharsis_managed_identity_access_rule resources for the same managed identity.",
+		Description: "Access rules of the managed identity. If set, rules not in the list are removed, " +
+			"so don't combine it with tharsis_managed_identity_access_rule resources for the same managed identity.",
+		Optional: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"type": schema.StringAttribute{
+					MarkdownDescription: "Type of access rule: eligible_principals or module_attestation.",
+					Description:         "Type of access rule: eligible_principals or module_attestation.",
+					Required:            true,
+					Validators: []validator.String{
+						validators.OneOf("eligible_principals", "module_attestation"),
+					},
+				},
+				"run_stage": schema.StringAttribute{
+					MarkdownDescription: "Type of job, plan or apply.",
+					Description:         "Type of job, plan or apply.",
+					Required:            true,
+					Validators: []validator.String{
+						validators.OneOf("plan", "apply"),
+					},
+				},
+				"allowed_users": schema.SetAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "List of usernames allowed to use the managed identity.",
+					Description:         "List of usernames allowed to use the managed identity.",
+					Optional:            true,
+				},
+				"allowed_service_accounts": schema.SetAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "List of resource paths of service accounts allowed to use the managed identity.",
+					Description:         "List of resource paths of service accounts allowed to use the managed identity.",
+					Optional:            true,
+				},
+				"allowed_teams": schema.SetAttribute{
+					ElementType:         types.StringType,
+					MarkdownDescription: "List of names of teams allowed to use the managed identity.",
+					Description:         "List of names of teams allowed to use the managed identity.",
+					Optional:            true,
+				},
+				"verify_state_lineage": schema.BoolAttribute{
+					MarkdownDescription: "Whether to verify that the workspace's current state is from the same module source, default is false.",
+					Description:         "Whether to verify that the workspace's current state is from the same module source, default is false.",
+					Optional:            true,
+				},
+				"module_attestation_policies": schema.ListNestedAttribute{
+					MarkdownDescription: "Used to verify that a module has an in-toto attestation that is signed with the specified public key and an optional predicate type.",
+					Description:         "Used to verify that a module has an in-toto attestation that is signed with the specified public key and an optional predicate type.",
+					Optional:            true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"predicate_type": schema.StringAttribute{
+								MarkdownDescription: "Optional predicate type for this attestation policy.",
+								Description:         "Optional predicate type for this attestation policy.",
+								Optional:            true,
+							},
+							"public_key": schema.StringAttribute{
+								MarkdownDescription: "Public key in PEM format for this attestation policy.",
+								Description:         "Public key in PEM format for this attestation policy.",
+								Required:            true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// inlineAccessRuleAttributes returns the attribute types of an access rule managed with its managed identity.
+func inlineAccessRuleAttributes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"type":                        types.StringType,
+		"run_stage":                   types.StringType,
+		"allowed_users":               types.SetType{ElemType: types.StringType},
+		"allowed_service_accounts":    types.SetType{ElemType: types.StringType},
+		"allowed_teams":               types.SetType{ElemType: types.StringType},
+		"verify_state_lineage":        types.BoolType,
+		"module_attestation_policies": types.ListType{ElemType: moduleAttestationPolicyType},
+	}
+}
+
+// getInlineAccessRules converts the access rules in a plan or state to SDK input.
+func getInlineAccessRules(ctx context.Context, list types.List) ([]ttypes.ManagedIdentityAccessRuleInput, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	models := []ManagedIdentityInlineAccessRuleModel{}
+	diags.Append(list.ElementsAs(ctx, &models, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	result := []ttypes.ManagedIdentityAccessRuleInput{}
+	for _, model := range models {
+		rule := ttypes.ManagedIdentityAccessRuleInput{
+			Type:                   ttypes.ManagedIdentityAccessRuleType(model.Type.ValueString()),
+			RunStage:               ttypes.JobType(model.RunStage.ValueString()),
+			AllowedUsers:           []string{},
+			AllowedServiceAccounts: []string{},
+			AllowedTeams:           []string{},
+		}
+		diags.Append(model.AllowedUsers.ElementsAs(ctx, &rule.AllowedUsers, false)...)
+		diags.Append(model.AllowedServiceAccounts.ElementsAs(ctx, &rule.AllowedServiceAccounts, false)...)
+		diags.Append(model.AllowedTeams.ElementsAs(ctx, &rule.AllowedTeams, false)...)
+		if !model.VerifyStateLineage.IsNull() {
+			rule.VerifyStateLineage = ptr.Bool(model.VerifyStateLineage.ValueBool())
+		}
+
+		policies := []ModuleAttestationPolicyModel{}
+		diags.Append(model.ModuleAttestationPolicies.ElementsAs(ctx, &policies, false)...)
+		for _, policy := range policies {
+			rule.ModuleAttestationPolicies = append(rule.ModuleAttestationPolicies,
+				ttypes.ManagedIdentityAccessRuleModuleAttestationPolicy{
+					PredicateType: policy.PredicateType,
+					PublicKey:     policy.PublicKey,
+				})
+		}
+
+		result = append(result, rule)
+	}
+
+	return result, diags
+}
+
+// toInlineAccessRuleInput converts an access rule from Tharsis to SDK input, so it can be compared with a plan.
+func toInlineAccessRuleInput(src ttypes.ManagedIdentityAccessRule) ttypes.ManagedIdentityAccessRuleInput {
+	rule := ttypes.ManagedIdentityAccessRuleInput{
+		Type:                      src.Type,
+		RunStage:                  src.RunStage,
+		AllowedUsers:              []string{},
+		AllowedServiceAccounts:    []string{},
+		AllowedTeams:              []string{},
+		VerifyStateLineage:        ptr.Bool(src.VerifyStateLineage),
+		ModuleAttestationPolicies: src.ModuleAttestationPolicies,
+	}
+	for _, user := range src.AllowedUsers {
+		rule.AllowedUsers = append(rule.AllowedUsers, user.Username)
+	}
+	for _, serviceAccount := range src.AllowedServiceAccounts {
+		rule.AllowedServiceAccounts = append(rule.AllowedServiceAccounts, serviceAccount.ResourcePath)
+	}
+	for _, team := range src.AllowedTeams {
+		rule.AllowedTeams = append(rule.AllowedTeams, team.Name)
+	}
+
+	return rule
+}
+
+// inlineAccessRuleKey returns a string that is the same for any two equivalent access rules.
+func inlineAccessRuleKey(rule ttypes.ManagedIdentityAccessRuleInput) string {
+	sorted := func(values []string) string {
+		copied := append([]string{}, values...)
+		sort.Strings(copied)
+		return strings.Join(copied, ",")
+	}
+
+	policies := []string{}
+	for _, policy := range rule.ModuleAttestationPolicies {
+		predicateType := ""
+		if policy.PredicateType != nil {
+			predicateType = *policy.PredicateType
+		}
+		policies = append(policies, predicateType+"="+strings.TrimSpace(policy.PublicKey))
+	}
+
+	verifyStateLineage := (rule.VerifyStateLineage != nil) && *rule.VerifyStateLineage
+
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%t|%s", rule.Type, rule.RunStage,
+		sorted(rule.AllowedUsers), sorted(rule.AllowedServiceAccounts), sorted(rule.AllowedTeams),
+		verifyStateLineage, sorted(policies))
+}
+
+// sameInlineAccessRules returns true if two lists have the same access rules, in any order.
+func sameInlineAccessRules(a, b []ttypes.ManagedIdentityAccessRuleInput) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := map[string]int{}
+	for _, rule := range a {
+		counts[inlineAccessRuleKey(rule)]++
+	}
+	for _, rule := range b {
+		key := inlineAccessRuleKey(rule)
+		if counts[key] == 0 {
+			return false
+		}
+		counts[key]--
+	}
+
+	return true
+}
+
+// toProviderInlineAccessRules converts access rules from Tharsis to a list for a state.
+func toProviderInlineAccessRules(ctx context.Context, rules []ttypes.ManagedIdentityAccessRule) (types.List, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	stringSet := func(values []string) types.Set {
+		if len(values) == 0 {
+			return types.SetNull(types.StringType)
+		}
+		value, setDiags := types.SetValueFrom(ctx, types.StringType, values)
+		diags.Append(setDiags...)
+		return value
+	}
+
+	models := []ManagedIdentityInlineAccessRuleModel{}
+	for _, src := range rules {
+		rule := toInlineAccessRuleInput(src)
+		model := ManagedIdentityInlineAccessRuleModel{
+			Type:                      types.StringValue(string(rule.Type)),
+			RunStage:                  types.StringValue(string(rule.RunStage)),
+			AllowedUsers:              stringSet(rule.AllowedUsers),
+			AllowedServiceAccounts:    stringSet(rule.AllowedServiceAccounts),
+			AllowedTeams:              stringSet(rule.AllowedTeams),
+			VerifyStateLineage:        types.BoolNull(),
+			ModuleAttestationPolicies: types.ListNull(moduleAttestationPolicyType),
+		}
+		if src.VerifyStateLineage {
+			model.VerifyStateLineage = types.BoolValue(true)
+		}
+		if len(src.ModuleAttestationPolicies) > 0 {
+			policies := []ModuleAttestationPolicyModel{}
+			for _, policy := range src.ModuleAttestationPolicies {
+				policies = append(policies, ModuleAttestationPolicyModel{
+					PredicateType: policy.PredicateType,
+					PublicKey:     policy.PublicKey,
+				})
+			}
+			var listDiags diag.Diagnostics
+			model.ModuleAttestationPolicies, listDiags = types.ListValueFrom(ctx, moduleAttestationPolicyType, policies)
+			diags.Append(listDiags...)
+		}
+		models = append(models, model)
+	}
+
+	list, listDiags := types.ListValueFrom(ctx, types.ObjectType{AttrTypes: inlineAccessRuleAttributes()}, models)
+	diags.Append(listDiags...)
+
+	return list, diags
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// relativePathPrefix marks a group or workspace path as relative to the provider's default_group_path.
+const relativePathPrefix = "./"
+
+// namespacePaths resolves group and workspace paths that are relative to the provider's default_group_path.
+type namespacePaths struct {
+	defaultGroupPath string
+}
+
+// resolve returns the full path of a group or workspace path.  A path starting with ./ is relative to
+// default_group_path, or to the root if it isn't set.  Any other path is already a full path.
+func (n namespacePaths) resolve(value string) string {
+	relative, ok := strings.CutPrefix(value, relativePathPrefix)
+	if !ok {
+		return value
+	}
+
+	if n.defaultGroupPath == "" {
+		return relative
+	}
+
+	return n.defaultGroupPath + "/" + relative
+}
+
+// keep returns the path in a plan or state if it resolves to the full path returned by Tharsis,
+// so that a relative path isn't replaced by the full path, and otherwise the full path.
+func (n namespacePaths) keep(current types.String, fullPath string) types.String {
+	if !current.IsNull() && !current.IsUnknown() && n.resolve(current.ValueString()) == fullPath {
+		return current
+	}
+
+	return types.StringValue(fullPath)
+}
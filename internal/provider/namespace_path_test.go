@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_namespacePaths(t *testing.T) {
+	for _, test := range []struct {
+		name             string
+		defaultGroupPath string
+		current          types.String
+		fullPath         string
+		wantResolved     string
+		wantKept         types.String
+	}{
+		{
+			name:             "relative path",
+			defaultGroupPath: "org/dev",
+			current:          types.StringValue("./team-a/workspace"),
+			fullPath:         "org/dev/team-a/workspace",
+			wantResolved:     "org/dev/team-a/workspace",
+			wantKept:         types.StringValue("./team-a/workspace"),
+		},
+		{
+			name:             "full path with a default group path",
+			defaultGroupPath: "org/dev",
+			current:          types.StringValue("org/prod/workspace"),
+			fullPath:         "org/prod/workspace",
+			wantResolved:     "org/prod/workspace",
+			wantKept:         types.StringValue("org/prod/workspace"),
+		},
+		{
+			name:         "relative path without a default group path",
+			current:      types.StringValue("./org/workspace"),
+			fullPath:     "org/workspace",
+			wantResolved: "org/workspace",
+			wantKept:     types.StringValue("./org/workspace"),
+		},
+		{
+			name:             "relative path moved outside Terraform",
+			defaultGroupPath: "org/dev",
+			current:          types.StringValue("./team-a/workspace"),
+			fullPath:         "org/dev/team-b/workspace",
+			wantResolved:     "org/dev/team-a/workspace",
+			wantKept:         types.StringValue("org/dev/team-b/workspace"),
+		},
+		{
+			name:             "imported",
+			defaultGroupPath: "org/dev",
+			current:          types.StringNull(),
+			fullPath:         "org/dev/workspace",
+			wantKept:         types.StringValue("org/dev/workspace"),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			paths := namespacePaths{defaultGroupPath: test.defaultGroupPath}
+			if got := paths.resolve(test.current.ValueString()); got != test.wantResolved {
+				t.Errorf("resolve(%q) = %q, want %q", test.current.ValueString(), got, test.wantResolved)
+			}
+			if got := paths.keep(test.current, test.fullPath); !got.Equal(test.wantKept) {
+				t.Errorf("keep(%s, %q) = %s, want %s", test.current, test.fullPath, got, test.wantKept)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// defaultProfileName is the profile used when the provider configuration doesn't name one.
+const defaultProfileName = "default"
+
+// tharsisProfile is a profile from the Tharsis CLI's settings file.
+type tharsisProfile struct {
+	Endpoint string `json:"endpoint"`
+	Token    string `json:"token"`
+}
+
+// tharsisSettings is the Tharsis CLI's settings file.
+type tharsisSettings struct {
+	Profiles map[string]tharsisProfile `json:"profiles"`
+}
+
+// tharsisSettingsPath returns the path of the Tharsis CLI's settings file.
+func tharsisSettingsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".tharsis", "settings.json"), nil
+}
+
+// loadProfile returns the profile named in the provider configuration or THARSIS_PROFILE,
+// or the default profile if no host is configured.  The default profile is only a fallback,
+// since the CLI is optional, so it returns nil if the settings file or the profile can't be read.
+// A named profile must exist.
+func loadProfile(pd *providerData) (*tharsisProfile, error) {
+	name := pd.Profile.ValueString()
+	if pd.Profile.IsNull() {
+		name = os.Getenv("THARSIS_PROFILE")
+	}
+	required := name != ""
+	if !required {
+		if !pd.Host.IsNull() || os.Getenv("THARSIS_ENDPOINT") != "" {
+			return nil, nil
+		}
+		name = defaultProfileName
+	}
+
+	settingsPath, err := tharsisSettingsPath()
+	if err != nil {
+		if required {
+			return nil, fmt.Errorf("failed to find the Tharsis settings file for profile %s: %v", name, err)
+		}
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(settingsPath)
+	if err != nil {
+		if !required {
+			return nil, nil
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("profile %s not found, because Tharsis settings file %s doesn't exist", name, settingsPath)
+		}
+		return nil, fmt.Errorf("failed to read Tharsis settings file %s: %v", settingsPath, err)
+	}
+
+	var settings tharsisSettings
+	if err := json.Unmarshal(contents, &settings); err != nil {
+		if !required {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse Tharsis settings file %s: %v", settingsPath, err)
+	}
+
+	profile, ok := settings.Profiles[name]
+	if !ok {
+		if required {
+			return nil, fmt.Errorf("profile %s not found in Tharsis settings file %s", name, settingsPath)
+		}
+		return nil, nil
+	}
+
+	return &profile, nil
+}
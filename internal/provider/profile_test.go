@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func Test_loadProfile(t *testing.T) {
+	settings := `{
+	"profiles": {
+		"default": {"endpoint": "https://tharsis.example.com", "token": "default-token"},
+		"staging": {"endpoint": "https://tharsis.staging.example.com", "token": "staging-token"}
+	}
+}`
+	malformed := `{"profiles": `
+
+	tests := []struct {
+		name       string
+		settings   *string
+		host       types.String
+		profile    types.String
+		envProfile string
+		wantNil    bool
+		wantErr    bool
+		wantToken  string
+	}{
+		{
+			name:      "Default profile is used when none is named",
+			settings:  &settings,
+			profile:   types.StringNull(),
+			wantToken: "default-token",
+		},
+		{
+			name:     "Default profile is not read when a host is configured",
+			settings: &settings,
+			host:     types.StringValue("https://tharsis.other.example.com"),
+			profile:  types.StringNull(),
+			wantNil:  true,
+		},
+		{
+			name:      "Named profile",
+			settings:  &settings,
+			profile:   types.StringValue("staging"),
+			wantToken: "staging-token",
+		},
+		{
+			name:       "Profile named in the environment",
+			settings:   &settings,
+			profile:    types.StringNull(),
+			envProfile: "staging",
+			wantToken:  "staging-token",
+		},
+		{
+			name:     "Missing named profile is an error",
+			settings: &settings,
+			profile:  types.StringValue("production"),
+			wantErr:  true,
+		},
+		{
+			name:    "Missing settings file without a named profile is ignored",
+			profile: types.StringNull(),
+			wantNil: true,
+		},
+		{
+			name:    "Missing settings file with a named profile is an error",
+			profile: types.StringValue("staging"),
+			wantErr: true,
+		},
+		{
+			name:     "Malformed settings file without a named profile is ignored",
+			settings: &malformed,
+			profile:  types.StringNull(),
+			wantNil:  true,
+		},
+		{
+			name:     "Malformed settings file with a named profile is an error",
+			settings: &malformed,
+			profile:  types.StringValue("staging"),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("THARSIS_PROFILE", tt.envProfile)
+			t.Setenv("THARSIS_ENDPOINT", "")
+
+			if tt.settings != nil {
+				if err := os.MkdirAll(filepath.Join(home, ".tharsis"), 0o700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(home, ".tharsis", "settings.json"), []byte(*tt.settings), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := loadProfile(&providerData{Host: tt.host, Profile: tt.profile})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("loadProfile() = %v, wantNil %v", got, tt.wantNil)
+			}
+			if got != nil && got.Token != tt.wantToken {
+				t.Errorf("loadProfile() token = %q, want %q", got.Token, tt.wantToken)
+			}
+		})
+	}
+}
@@ -13,9 +13,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	"gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/auth"
@@ -40,6 +42,14 @@ func New() provider.Provider {
 type tharsisProvider struct {
 	// client is the Tharsis SDK Client that will be used to make the API calls.
 	client *tharsis.Client
+	// registry is the client of the Terraform registry protocol served by the Tharsis API.
+	registry *registryClient
+	// runLimiter limits the number of concurrent runs launched by tharsis_apply_module resources.
+	runLimiter *runLimiter
+	// workspaces shares the workspaces read while refreshing between resources and data sources.
+	workspaces *workspaceCache
+	// paths resolves the group and workspace paths of resources that are relative to default_group_path.
+	paths namespacePaths
 	// version is set to the provider version on release, "dev" when the
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
@@ -65,6 +75,11 @@ func (p *tharsisProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				MarkdownDescription: "This is the hostname for the Tharsis API (e.g. https://tharsis.example.com).",
 				Optional:            true,
 			},
+			"profile": schema.StringAttribute{
+				Description:         "Name of the Tharsis CLI profile to read the host and token from",
+				MarkdownDescription: "The name of the Tharsis CLI profile in `~/.tharsis/settings.json` to read the host and token from, when they aren't set otherwise. Can also be set with the `THARSIS_PROFILE` environment variable. If no profile is named and no host is set, the `default` profile is used if it exists. The profile's token has the lowest priority, below `TF_TOKEN_<host>`.",
+				Optional:            true,
+			},
 			"static_token": schema.StringAttribute{
 				Description:         "Static token to authenticate with the Tharsis API",
 				MarkdownDescription: "A static token to use to authenticate with the Tharsis API.",
@@ -80,6 +95,30 @@ func (p *tharsisProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 				MarkdownDescription: "A Service account token to use for authenticating with the Tharsis API.",
 				Optional:            true,
 			},
+			"oidc_token": schema.StringAttribute{
+				Description:         "OIDC token from a trusted issuer, used to log in as the service account",
+				MarkdownDescription: "An OIDC token from an issuer trusted by the service account, such as a GitLab CI or GitHub Actions ID token, used to log in as `service_account_path`. Can also be set with the `THARSIS_OIDC_TOKEN` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"oidc_token_file": schema.StringAttribute{
+				Description:         "Path of a file containing an OIDC token, used to log in as the service account",
+				MarkdownDescription: "The path of a file containing an OIDC token, used to log in as `service_account_path`. The file is read again whenever the provider logs in, so the token can be rotated. Can also be set with the `THARSIS_OIDC_TOKEN_FILE` environment variable.",
+				Optional:            true,
+			},
+			"max_concurrent_runs": schema.Int64Attribute{
+				Description:         "Maximum number of runs that tharsis_apply_module resources launch at once",
+				MarkdownDescription: "The maximum number of runs that tharsis_apply_module resources launch at once. Other runs wait in a queue. Unlimited by default.",
+				Optional:            true,
+			},
+			"default_group_path": schema.StringAttribute{
+				Description:         "Full path of the group that relative group and workspace paths of resources are in",
+				MarkdownDescription: "The full path of the group that relative group and workspace paths of resources are in. A `group_path`, `parent_path`, `namespace_path` or `workspace_path` starting with `./`, such as `./team-a/workspace`, is relative to this group, so the same configuration can manage different group trees. Without it, relative paths are relative to the root. Imported resources have full paths.",
+				Optional:            true,
+				Validators: []validator.String{
+					validators.ResourcePath(),
+				},
+			},
 		},
 	}
 }
@@ -87,9 +126,14 @@ func (p *tharsisProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 // providerData can be used to store data from the Terraform configuration.
 type providerData struct {
 	Host                types.String `tfsdk:"host"`
+	Profile             types.String `tfsdk:"profile"`
 	StaticToken         types.String `tfsdk:"static_token"`
 	ServiceAccountPath  types.String `tfsdk:"service_account_path"`
 	ServiceAccountToken types.String `tfsdk:"service_account_token"`
+	OIDCToken           types.String `tfsdk:"oidc_token"`
+	OIDCTokenFile       types.String `tfsdk:"oidc_token_file"`
+	MaxConcurrentRuns   types.Int64  `tfsdk:"max_concurrent_runs"`
+	DefaultGroupPath    types.String `tfsdk:"default_group_path"`
 }
 
 // checkUnknowns validates that no field is unknown during configuration
@@ -105,6 +149,15 @@ func (pd *providerData) checkUnknowns() diag.Diagnostics {
 		)
 	}
 
+	if pd.Profile.IsUnknown() {
+		diags = append(diags,
+			diag.NewErrorDiagnostic(
+				"Unknown profile",
+				"Cannot use an unknown value as profile",
+			),
+		)
+	}
+
 	if pd.StaticToken.IsUnknown() {
 		diags = append(diags,
 			diag.NewErrorDiagnostic(
@@ -132,6 +185,42 @@ func (pd *providerData) checkUnknowns() diag.Diagnostics {
 		)
 	}
 
+	if pd.OIDCToken.IsUnknown() {
+		diags = append(diags,
+			diag.NewErrorDiagnostic(
+				"Unknown OIDC token",
+				"Cannot use an unknown value as OIDC token",
+			),
+		)
+	}
+
+	if pd.OIDCTokenFile.IsUnknown() {
+		diags = append(diags,
+			diag.NewErrorDiagnostic(
+				"Unknown OIDC token file",
+				"Cannot use an unknown value as OIDC token file",
+			),
+		)
+	}
+
+	if pd.MaxConcurrentRuns.IsUnknown() {
+		diags = append(diags,
+			diag.NewErrorDiagnostic(
+				"Unknown max concurrent runs",
+				"Cannot use an unknown value as max concurrent runs",
+			),
+		)
+	}
+
+	if pd.DefaultGroupPath.IsUnknown() {
+		diags = append(diags,
+			diag.NewErrorDiagnostic(
+				"Unknown default group path",
+				"Cannot use an unknown value as default group path",
+			),
+		)
+	}
+
 	return diags
 }
 
@@ -151,7 +240,25 @@ func (p *tharsisProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	tClient, err := newTharsisClient(ctx, &data)
+	profile, err := loadProfile(&data)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading Tharsis CLI profile",
+			err.Error(),
+		)
+		return
+	}
+
+	host, err := resolveHost(&data, profile)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error configuring the Tharsis client",
+			fmt.Sprintf("Error configuring the Tharsis client, this is an error in the provider.\n%s\n", err),
+		)
+		return
+	}
+
+	tClient, registry, err := newTharsisClient(ctx, host, &data, profile)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error configuring the Tharsis client",
@@ -160,12 +267,18 @@ func (p *tharsisProvider) Configure(ctx context.Context, req provider.ConfigureR
 	}
 
 	p.client = tClient
+	p.registry = registry
+	p.runLimiter = newRunLimiter(int(data.MaxConcurrentRuns.ValueInt64()))
+	if tClient != nil {
+		p.workspaces = newWorkspaceCache(tClient.Workspaces)
+	}
+	p.paths = namespacePaths{defaultGroupPath: data.DefaultGroupPath.ValueString()}
 	p.configured = true
 
-	// Make the Tharsis client available during DataSource and Resource
-	// type Configure methods.
+	// Make the Tharsis client available during DataSource type Configure methods,
+	// and the provider, including the client, during Resource type Configure methods.
 	resp.DataSourceData = tClient
-	resp.ResourceData = tClient
+	resp.ResourceData = p
 
 	tflog.Info(ctx, "Configured Tharsis client", map[string]any{"success": true})
 }
@@ -174,16 +287,20 @@ func (p *tharsisProvider) Resources(context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		NewGPGKeyResource,
 		NewGroupResource,
+		NewGroupTreeResource,
 		NewManagedIdentityResource,
 		NewManagedIdentityAliasResource,
 		NewManagedIdentityAccessRuleResource,
 		NewServiceAccountResource,
 		NewTerraformModuleResource,
 		NewTerraformProviderResource,
+		NewTerraformProviderVersionMirrorResource,
 		NewVariableResource,
+		NewVariableSetResource,
 		NewVCSProviderResource,
 		NewWorkspaceResource,
 		NewApplyModuleResource,
+		NewRunApprovalResource,
 		NewWorkspaceVCSProviderLinkResource,
 		NewAssignedManagedIdentityResource,
 	}
@@ -207,20 +324,83 @@ func (p *tharsisProvider) DataSources(context.Context) []func() datasource.DataS
 				isJSONEncoded: true,
 			}
 		},
+
+		// tharsis_job_logs
+		func() datasource.DataSource {
+			return jobLogsDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_workspaces
+		func() datasource.DataSource {
+			return workspacesDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_namespace_memberships
+		func() datasource.DataSource {
+			return namespaceMembershipsDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_users
+		func() datasource.DataSource {
+			return usersDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_team
+		func() datasource.DataSource {
+			return teamDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_gpg_key
+		func() datasource.DataSource {
+			return gpgKeyDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_terraform_module
+		func() datasource.DataSource {
+			return terraformModuleDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_terraform_modules
+		func() datasource.DataSource {
+			return terraformModulesDataSource{
+				provider: *p,
+			}
+		},
+
+		// tharsis_terraform_provider_versions
+		func() datasource.DataSource {
+			return terraformProviderVersionsDataSource{
+				provider: *p,
+			}
+		},
 	}
 }
 
-func newTharsisClient(_ context.Context, pd *providerData) (*tharsis.Client, error) {
-	var (
-		host                                    string
-		staticToken                             string
-		serviceAccountPath, serviceAccountToken string
-		optFn                                   []func(*config.LoadOptions) error
-	)
+// resolveHost returns the URL of the Tharsis API from the provider configuration, the environment
+// or the Tharsis CLI profile, in that order.
+func resolveHost(pd *providerData, profile *tharsisProfile) (string, error) {
+	var host string
 
 	// User must specify a host
 	if pd.Host.IsNull() {
 		host = os.Getenv("THARSIS_ENDPOINT")
+		if (host == "") && (profile != nil) {
+			host = profile.Endpoint
+		}
 	} else {
 		host = pd.Host.ValueString()
 
@@ -232,17 +412,44 @@ func newTharsisClient(_ context.Context, pd *providerData) (*tharsis.Client, err
 	}
 
 	if host == "" {
-		return nil, fmt.Errorf("host cannot be an empty string")
+		return "", fmt.Errorf("host cannot be an empty string")
 	}
+
+	return host, nil
+}
+
+// newTharsisClient returns the SDK client and, using the same token, a client of the
+// Terraform registry protocol served by the Tharsis API.
+func newTharsisClient(_ context.Context, host string, pd *providerData, profile *tharsisProfile,
+) (*tharsis.Client, *registryClient, error) {
+	var (
+		staticToken, serviceAccountPath string
+		optFn                           []func(*config.LoadOptions) error
+		// getToken is the GetToken method of the last token provider, which is the one the SDK uses.
+		getToken func() (string, error)
+	)
+
 	optFn = append(optFn, config.WithEndpoint(host))
 
-	// Add TF_TOKEN_<host> value as first optFn as it is lowest priority
+	// Add the Tharsis CLI profile's token as first optFn as it is lowest priority.
+	// The profile's token is only for its own endpoint.
+	if (profile != nil) && (profile.Token != "") && (strings.TrimSuffix(profile.Endpoint, "/") == strings.TrimSuffix(host, "/")) {
+		tokenProvider, err := auth.NewStaticTokenProvider(profile.Token)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to obtain a token provider for the Tharsis CLI profile: %v", err)
+		}
+		optFn = append(optFn, config.WithTokenProvider(tokenProvider))
+		getToken = tokenProvider.GetToken
+	}
+
+	// TF_TOKEN_<host> takes priority over the profile's token
 	if token := getTFTokenForHost(host); token != "" {
 		tokenProvider, err := auth.NewStaticTokenProvider(token)
 		if err != nil {
-			return nil, fmt.Errorf("failed to obtain a token provider for host \"%s\" using \"TF_TOKEN_\" environment variable: %v", host, err)
+			return nil, nil, fmt.Errorf("failed to obtain a token provider for host \"%s\" using \"TF_TOKEN_\" environment variable: %v", host, err)
 		}
 		optFn = append(optFn, config.WithTokenProvider(tokenProvider))
+		getToken = tokenProvider.GetToken
 	}
 
 	if pd.StaticToken.IsNull() {
@@ -254,9 +461,10 @@ func newTharsisClient(_ context.Context, pd *providerData) (*tharsis.Client, err
 	if staticToken != "" {
 		tokenProvider, err := auth.NewStaticTokenProvider(staticToken)
 		if err != nil {
-			return nil, fmt.Errorf("failed to obtain a token provider for static token: %v", err)
+			return nil, nil, fmt.Errorf("failed to obtain a token provider for static token: %v", err)
 		}
 		optFn = append(optFn, config.WithTokenProvider(tokenProvider))
+		getToken = tokenProvider.GetToken
 	}
 
 	if pd.ServiceAccountPath.IsNull() {
@@ -265,29 +473,83 @@ func newTharsisClient(_ context.Context, pd *providerData) (*tharsis.Client, err
 		serviceAccountPath = pd.ServiceAccountPath.ValueString()
 	}
 
-	if pd.ServiceAccountToken.IsNull() {
-		serviceAccountToken = os.Getenv("THARSIS_SERVICE_ACCOUNT_TOKEN")
-	} else {
-		serviceAccountToken = pd.ServiceAccountToken.ValueString()
+	getServiceAccountToken, err := serviceAccountTokenGetter(pd)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if (serviceAccountPath != "") && (serviceAccountToken != "") {
-		tokenProvider, err := auth.NewServiceAccountTokenProvider(host, serviceAccountPath,
-			func() (string, error) {
-				return serviceAccountToken, nil
-			})
+	if (serviceAccountPath != "") && (getServiceAccountToken != nil) {
+		tokenProvider, err := auth.NewServiceAccountTokenProvider(host, serviceAccountPath, getServiceAccountToken)
 		if err != nil {
-			return nil, fmt.Errorf("failed to obtain a token provider for service account %s: %v", serviceAccountPath, err)
+			return nil, nil, fmt.Errorf("failed to obtain a token provider for service account %s: %v", serviceAccountPath, err)
 		}
 		optFn = append(optFn, config.WithTokenProvider(tokenProvider))
+		getToken = tokenProvider.GetToken
 	}
 
 	sdkConfig, err := config.Load(optFn...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	client, err := tharsis.NewClient(sdkConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, newRegistryClient(host, getToken), nil
+}
+
+// serviceAccountTokenGetter returns a function that gets the token to log in as a service account.
+// The token is a service account token or an OIDC token, given directly or in a file, in that order
+// of precedence.  Only one of them can be configured, and then the environment variables are ignored.
+// It returns nil if none of them is set.
+func serviceAccountTokenGetter(pd *providerData) (func() (string, error), error) {
+	serviceAccountToken := pd.ServiceAccountToken.ValueString()
+	oidcToken := pd.OIDCToken.ValueString()
+	oidcTokenFile := pd.OIDCTokenFile.ValueString()
+
+	set := 0
+	for _, value := range []string{serviceAccountToken, oidcToken, oidcTokenFile} {
+		if value != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("only one of service_account_token, oidc_token, and oidc_token_file can be set")
+	}
+
+	if set == 0 {
+		serviceAccountToken = os.Getenv("THARSIS_SERVICE_ACCOUNT_TOKEN")
+		oidcToken = os.Getenv("THARSIS_OIDC_TOKEN")
+		oidcTokenFile = os.Getenv("THARSIS_OIDC_TOKEN_FILE")
+	}
+
+	switch {
+	case serviceAccountToken != "":
+		return func() (string, error) {
+			return serviceAccountToken, nil
+		}, nil
+	case oidcToken != "":
+		return func() (string, error) {
+			return oidcToken, nil
+		}, nil
+	case oidcTokenFile != "":
+		// Read the file on every login, since CI systems may replace the token before it expires.
+		return func() (string, error) {
+			contents, err := os.ReadFile(oidcTokenFile)
+			if err != nil {
+				return "", fmt.Errorf("failed to read OIDC token file: %v", err)
+			}
+			token := strings.TrimSpace(string(contents))
+			if token == "" {
+				return "", fmt.Errorf("OIDC token file %s is empty", oidcTokenFile)
+			}
+			return token, nil
+		}, nil
 	}
 
-	return tharsis.NewClient(sdkConfig)
+	return nil, nil
 }
 
 func getTFTokenForHost(host string) string {
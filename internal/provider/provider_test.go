@@ -1,9 +1,12 @@
 package provider
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
@@ -43,3 +46,112 @@ provider "tharsis" {
 }
 	`
 }
+
+func Test_serviceAccountTokenGetter(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		pd         providerData
+		env        map[string]string
+		want       string
+		wantNil    bool
+		wantErr    bool
+		wantGetErr bool
+	}{
+		{
+			name:    "Nothing set returns nil",
+			pd:      providerData{},
+			wantNil: true,
+		},
+		{
+			name: "Service account token",
+			pd: providerData{
+				ServiceAccountToken: types.StringValue("sa-token"),
+			},
+			want: "sa-token",
+		},
+		{
+			name: "OIDC token",
+			pd: providerData{
+				OIDCToken: types.StringValue("oidc-token"),
+			},
+			want: "oidc-token",
+		},
+		{
+			name: "OIDC token file is read and trimmed",
+			pd: providerData{
+				OIDCTokenFile: types.StringValue(tokenFile),
+			},
+			want: "file-token",
+		},
+		{
+			name: "Missing OIDC token file fails when getting the token",
+			pd: providerData{
+				OIDCTokenFile: types.StringValue(filepath.Join(t.TempDir(), "missing")),
+			},
+			wantGetErr: true,
+		},
+		{
+			name: "Configured token takes precedence over environment variables",
+			pd: providerData{
+				OIDCToken: types.StringValue("oidc-token"),
+			},
+			env: map[string]string{
+				"THARSIS_SERVICE_ACCOUNT_TOKEN": "env-sa-token",
+			},
+			want: "oidc-token",
+		},
+		{
+			name: "More than one environment variable is not an error",
+			env: map[string]string{
+				"THARSIS_SERVICE_ACCOUNT_TOKEN": "env-sa-token",
+				"THARSIS_OIDC_TOKEN":            "env-oidc-token",
+			},
+			want: "env-sa-token",
+		},
+		{
+			name: "More than one token is an error",
+			pd: providerData{
+				ServiceAccountToken: types.StringValue("sa-token"),
+				OIDCToken:           types.StringValue("oidc-token"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("THARSIS_SERVICE_ACCOUNT_TOKEN", "")
+			t.Setenv("THARSIS_OIDC_TOKEN", "")
+			t.Setenv("THARSIS_OIDC_TOKEN_FILE", "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			got, err := serviceAccountTokenGetter(&tt.pd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("serviceAccountTokenGetter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("serviceAccountTokenGetter() returned nil = %v, want %v", got == nil, tt.wantNil)
+			}
+			if tt.wantNil {
+				return
+			}
+
+			token, err := got()
+			if (err != nil) != tt.wantGetErr {
+				t.Fatalf("getting token error = %v, wantGetErr %v", err, tt.wantGetErr)
+			}
+			if token != tt.want {
+				t.Errorf("getting token = %q, want %q", token, tt.want)
+			}
+		})
+	}
+}
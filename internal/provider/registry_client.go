@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// registryDiscoveryPath is where a Terraform registry host lists the services it provides.
+const registryDiscoveryPath = "/.well-known/terraform.json"
+
+// registryRequestTimeout limits how long a request to the registry can take, including reading the response.
+const registryRequestTimeout = time.Minute
+
+// registryClient reads the Terraform provider registry protocol served by the Tharsis API,
+// for information about provider versions that the SDK doesn't expose.
+type registryClient struct {
+	host       string
+	httpClient *http.Client
+	// getToken returns the token to send, or nil if requests aren't authenticated.
+	getToken func() (string, error)
+}
+
+// newRegistryClient returns a registry client for a Tharsis host, authenticating with getToken if it isn't nil.
+// The SDK doesn't expose its HTTP client, so the registry client has its own.
+func newRegistryClient(host string, getToken func() (string, error)) *registryClient {
+	return &registryClient{
+		host:       host,
+		httpClient: &http.Client{Timeout: registryRequestTimeout},
+		getToken:   getToken,
+	}
+}
+
+// registryProviderVersion is a version of a provider, as listed by the registry.
+type registryProviderVersion struct {
+	Version   string                     `json:"version"`
+	Protocols []string                   `json:"protocols"`
+	Platforms []registryProviderPlatform `json:"platforms"`
+}
+
+// registryProviderPlatform is a platform that a version of a provider was built for.
+type registryProviderPlatform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+// registryProviderPackage describes the package of a provider version for one platform.
+type registryProviderPackage struct {
+	Filename string `json:"filename"`
+	SHASum   string `json:"shasum"`
+}
+
+// getProviderVersions returns the versions of a provider in the registry.
+func (c *registryClient) getProviderVersions(ctx context.Context, namespace, name string,
+) ([]registryProviderVersion, error) {
+	var output struct {
+		Versions []registryProviderVersion `json:"versions"`
+	}
+	if err := c.getProviderResource(ctx, &output, namespace, name, "versions"); err != nil {
+		return nil, err
+	}
+
+	return output.Versions, nil
+}
+
+// getProviderPackage returns the package of a provider version for one platform.
+func (c *registryClient) getProviderPackage(ctx context.Context, namespace, name, version string,
+	platform registryProviderPlatform,
+) (*registryProviderPackage, error) {
+	var output registryProviderPackage
+	if err := c.getProviderResource(ctx, &output, namespace, name, version, "download", platform.OS, platform.Arch); err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// getProviderResource decodes a resource of the providers.v1 registry service into output.
+func (c *registryClient) getProviderResource(ctx context.Context, output any, pathSegments ...string) error {
+	baseURL, err := c.discoverService(ctx, "providers.v1")
+	if err != nil {
+		return err
+	}
+
+	escaped := make([]string, len(pathSegments))
+	for i, segment := range pathSegments {
+		escaped[i] = url.PathEscape(segment)
+	}
+
+	resourceURL, err := baseURL.Parse(strings.Join(escaped, "/"))
+	if err != nil {
+		return fmt.Errorf("failed to build registry URL: %v", err)
+	}
+
+	return c.getJSON(ctx, resourceURL.String(), output)
+}
+
+// discoverService returns the base URL of a registry service, ending in a slash.
+func (c *registryClient) discoverService(ctx context.Context, service string) (*url.URL, error) {
+	hostURL, err := url.Parse(strings.TrimSuffix(c.host, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse host %s: %v", c.host, err)
+	}
+
+	var services map[string]any
+	if err = c.getJSON(ctx, hostURL.String()+registryDiscoveryPath, &services); err != nil {
+		return nil, err
+	}
+
+	path, ok := services[service].(string)
+	if !ok {
+		return nil, fmt.Errorf("host %s does not provide the %s registry service", c.host, service)
+	}
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	return hostURL.Parse(path)
+}
+
+// getJSON sends a GET request and decodes the JSON response into output.
+func (c *registryClient) getJSON(ctx context.Context, requestURL string, output any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	if c.getToken != nil {
+		token, err := c.getToken()
+		if err != nil {
+			return fmt.Errorf("failed to get a token for the registry: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry request %s failed: %s", requestURL, resp.Status)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(output); err != nil {
+		return fmt.Errorf("failed to decode registry response from %s: %v", requestURL, err)
+	}
+
+	return nil
+}
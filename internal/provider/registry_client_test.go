@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_registryClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/terraform.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"providers.v1": "/v1/providers/"})
+	})
+	mux.HandleFunc("/v1/providers/top-group/example/versions", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer some-token" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer some-token")
+		}
+		w.Write([]byte(`{"versions":[{"version":"1.2.0","protocols":["5.0"],"platforms":[{"os":"linux","arch":"amd64"}]}]}`))
+	})
+	mux.HandleFunc("/v1/providers/top-group/example/1.2.0/download/linux/amd64", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"os":"linux","arch":"amd64","filename":"terraform-provider-example_1.2.0_linux_amd64.zip","shasum":"abc123"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := newRegistryClient(server.URL+"/", func() (string, error) { return "some-token", nil })
+
+	versions, err := client.getProviderVersions(context.Background(), "top-group", "example")
+	if err != nil {
+		t.Fatalf("getProviderVersions() unexpected error: %v", err)
+	}
+	wantVersions := []registryProviderVersion{{
+		Version:   "1.2.0",
+		Protocols: []string{"5.0"},
+		Platforms: []registryProviderPlatform{{OS: "linux", Arch: "amd64"}},
+	}}
+	if !reflect.DeepEqual(versions, wantVersions) {
+		t.Errorf("getProviderVersions() = %+v, want %+v", versions, wantVersions)
+	}
+
+	pkg, err := client.getProviderPackage(context.Background(), "top-group", "example", "1.2.0", versions[0].Platforms[0])
+	if err != nil {
+		t.Fatalf("getProviderPackage() unexpected error: %v", err)
+	}
+	wantPackage := &registryProviderPackage{Filename: "terraform-provider-example_1.2.0_linux_amd64.zip", SHASum: "abc123"}
+	if !reflect.DeepEqual(pkg, wantPackage) {
+		t.Errorf("getProviderPackage() = %+v, want %+v", pkg, wantPackage)
+	}
+
+	if _, err = client.getProviderVersions(context.Background(), "top-group", "missing"); err == nil {
+		t.Errorf("getProviderVersions() of a missing provider returned no error")
+	}
+}
@@ -2,7 +2,15 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -10,14 +18,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	sdktypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -32,16 +45,39 @@ const (
 
 	// lookForStateCreation is the string to look for in the logs to find the state creation message.
 	lookForStateCreation = "Created new state version"
+
+	// appliedVariablesPrivateStateKey is the private state key of the variables used by the latest apply.
+	appliedVariablesPrivateStateKey = "applied_variables"
+
+	// configurationVersionPollInterval is how often to check whether a configuration version has been uploaded.
+	configurationVersionPollInterval = 2 * time.Second
 )
 
 type createRunInput struct {
-	model     *ApplyModuleModel
-	doDestroy bool
+	model        *ApplyModuleModel
+	doDestroy    bool
+	pollInterval time.Duration
+	streamLogs   bool
+	// variables, if set, are used instead of the model's variables.
+	variables []sdktypes.RunVariable
+	// detectingDrift does a quiet speculative plan regardless of the model.
+	detectingDrift bool
+	// noWaitForApply returns as soon as the apply has been started.
+	noWaitForApply bool
+	// retry is set by createRun from the model.
+	retry retryPolicy
 }
 
 type createRunOutput struct {
-	moduleVersion     string
-	resolvedVariables []sdktypes.RunVariable
+	runID                  string
+	status                 string
+	configurationVersionID string
+	moduleVersion          string
+	resolvedVariables      []sdktypes.RunVariable
+	resourceAdditions      int64
+	resourceChanges        int64
+	resourceDestructions   int64
+	changesDetected        bool
 }
 
 // appliedModuleInfo contains what information was available about the latest applied run.
@@ -49,22 +85,48 @@ type createRunOutput struct {
 type appliedModuleInfo struct {
 	moduleSource         *string
 	moduleVersion        *string
+	stateVersion         *sdktypes.StateVersion
 	wasSuccessfulDestroy bool
 	wasManualUpdate      bool
 }
 
+// jobTerminalStatuses are the statuses of a job that has ended.  A job normally ends as finished,
+// whether or not it succeeded, but the others end the wait too rather than polling forever.
+var jobTerminalStatuses = map[string]bool{
+	"finished": true,
+	"errored":  true,
+	"canceled": true,
+	"failed":   true,
+}
+
 const (
 	jobCompletionPollInterval = 5 * time.Second
+
+	// runCancellationTimeout is how long to wait for the API to acknowledge the cancellation of a run.
+	runCancellationTimeout = 30 * time.Second
+
+	// runCancellationPollInterval is how often to check whether a run has been canceled.
+	runCancellationPollInterval = 2 * time.Second
 )
 
 var applyRunComment = "terraform-provider-tharsis" // must be var, not const, to take address
 
+// appliedVariable is a variable supplied to the latest apply, kept in private state to replay on destroy.
+type appliedVariable struct {
+	Value    *string `json:"value"`
+	Key      string  `json:"key"`
+	Category string  `json:"category"`
+}
+
 // RunVariableModel is used in apply modules to set Terraform and environment variables.
+// Sensitive is only set on input variables; sensitive variables are omitted from the resolved variables.
+// An input variable with a namespace path has no value, since it refers to the variable of that namespace.
 type RunVariableModel struct {
 	Value         string `tfsdk:"value"`
 	NamespacePath string `tfsdk:"namespace_path"`
 	Key           string `tfsdk:"key"`
 	Category      string `tfsdk:"category"`
+	Sensitive     bool   `tfsdk:"-"`
 }
 
 // FromTerraform5Value converts a RunVariable from Terraform values to Go equivalent.
@@ -82,6 +144,14 @@ func (e *RunVariableModel) FromTerraform5Value(val tftypes.Value) error {
 		return err
 	}
 
+	// Input variables only have a namespace path if they refer to a namespace's variable.
+	if namespacePath, ok := v["namespace_path"]; ok {
+		err = namespacePath.As(&e.NamespacePath)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = v["key"].As(&e.Key)
 	if err != nil {
 		return err
@@ -92,26 +162,81 @@ func (e *RunVariableModel) FromTerraform5Value(val tftypes.Value) error {
 		return err
 	}
 
+	// The sensitive flag is optional.
+	if sensitive, ok := v["sensitive"]; ok && !sensitive.IsNull() {
+		err = sensitive.As(&e.Sensitive)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// ApplyModuleTimeoutsModel holds the optional per-operation timeouts of an apply_module.
+type ApplyModuleTimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+}
+
+// ApplyModuleRetryModel holds the optional retry policy for the API calls of an apply_module.
+type ApplyModuleRetryModel struct {
+	MaxAttempts    types.Int64  `tfsdk:"max_attempts"`
+	InitialBackoff types.String `tfsdk:"initial_backoff"`
+	MaxBackoff     types.String `tfsdk:"max_backoff"`
+	RetryOn        types.List   `tfsdk:"retry_on"`
+}
+
 // ApplyModuleModel is the model for an apply_module.
 // Please note: Unlike many/most other resources, this model does not exist in the Tharsis API.
 // The workspace path, module source, and module version uniquely identify this apply_module.
 type ApplyModuleModel struct {
-	ID                types.String        `tfsdk:"id"`
-	WorkspacePath     types.String        `tfsdk:"workspace_path"`
-	ModuleSource      types.String        `tfsdk:"module_source"`
-	ModuleVersion     types.String        `tfsdk:"module_version"`
-	Refresh           types.Bool          `tfsdk:"refresh"`
-	Variables         basetypes.ListValue `tfsdk:"variables"`
-	ResolvedVariables basetypes.ListValue `tfsdk:"resolved_variables"`
+	ID                          types.String        `tfsdk:"id"`
+	WorkspacePath               types.String        `tfsdk:"workspace_path"`
+	ModuleSource                types.String        `tfsdk:"module_source"`
+	ModuleVersion               types.String        `tfsdk:"module_version"`
+	Directory                   types.String        `tfsdk:"directory"`
+	DirectoryHash               types.String        `tfsdk:"directory_hash"`
+	ConfigurationVersionID      types.String        `tfsdk:"configuration_version_id"`
+	Refresh                     types.Bool          `tfsdk:"refresh"`
+	RefreshOnly                 types.Bool          `tfsdk:"refresh_only"`
+	TargetAddresses             types.List          `tfsdk:"target_addresses"`
+	Triggers                    types.Map           `tfsdk:"triggers"`
+	ExpectedModuleDigest        types.String        `tfsdk:"expected_module_digest"`
+	Speculative                 types.Bool          `tfsdk:"speculative"`
+	DetectDrift                 types.Bool          `tfsdk:"detect_drift"`
+	WaitForCompletion           types.Bool          `tfsdk:"wait_for_completion"`
+	AutoApprove                 types.Bool          `tfsdk:"auto_approve"`
+	Status                      types.String        `tfsdk:"status"`
+	DriftDetected               types.Bool          `tfsdk:"drift_detected"`
+	StreamLogs                  types.Bool          `tfsdk:"stream_logs"`
+	DestroyWithAppliedVariables types.Bool          `tfsdk:"destroy_with_applied_variables"`
+	DestroyOnDelete             types.Bool          `tfsdk:"destroy_on_delete"`
+	Variables                   basetypes.SetValue  `tfsdk:"variables"`
+	EnvironmentVariables        types.Map           `tfsdk:"environment_variables"`
+	ResolvedVariables           basetypes.ListValue `tfsdk:"resolved_variables"`
+	Outputs                     types.Map           `tfsdk:"outputs"`
+	OutputsJSON                 types.Map           `tfsdk:"outputs_json"`
+	RunID                       types.String        `tfsdk:"run_id"`
+	StateVersionID              types.String        `tfsdk:"state_version_id"`
+	ResourceAdditions           types.Int64         `tfsdk:"resource_additions"`
+	ResourceChanges             types.Int64         `tfsdk:"resource_changes"`
+	ResourceDestructions        types.Int64         `tfsdk:"resource_destructions"`
+	ChangesDetected             types.Bool          `tfsdk:"changes_detected"`
+	Timeouts                    types.Object        `tfsdk:"timeouts"`
+	Retry                       types.Object        `tfsdk:"retry"`
+	JobPollInterval             types.String        `tfsdk:"job_poll_interval"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource              = (*applyModuleResource)(nil)
-	_ resource.ResourceWithConfigure = (*applyModuleResource)(nil)
+	_ resource.Resource                   = (*applyModuleResource)(nil)
+	_ resource.ResourceWithConfigure      = (*applyModuleResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*applyModuleResource)(nil)
+	_ resource.ResourceWithUpgradeState   = (*applyModuleResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*applyModuleResource)(nil)
+	_ resource.ResourceWithImportState    = (*applyModuleResource)(nil)
 )
 
 // NewApplyModuleResource is a helper function to simplify the provider implementation.
@@ -120,7 +245,10 @@ func NewApplyModuleResource() resource.Resource {
 }
 
 type applyModuleResource struct {
-	client *tharsis.Client
+	client     *applyModuleClient
+	runLimiter *runLimiter
+	paths      namespacePaths
+	workspaces *workspaceCache
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -134,7 +262,7 @@ func (t *applyModuleResource) Schema(_ context.Context, _ resource.SchemaRequest
 	description := "Defines and manages tharsis_apply_module resources, which launch runs in other workspaces."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -150,20 +278,43 @@ func (t *applyModuleResource) Schema(_ context.Context, _ resource.SchemaRequest
 				MarkdownDescription: "The full path of the workspace.",
 				Description:         "The full path of the workspace.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"module_source": schema.StringAttribute{
-				MarkdownDescription: "The source of the module.",
-				Description:         "The source of the module.",
-				Required:            true,
+				MarkdownDescription: "The source of the module. Exactly one of module_source or directory must be set.",
+				Description:         "The source of the module. Exactly one of module_source or directory must be set.",
+				Optional:            true,
+			},
+			"directory": schema.StringAttribute{
+				MarkdownDescription: "The path of a local directory to upload as a configuration version and run in the target workspace. " +
+					"Exactly one of module_source or directory must be set.",
+				Description: "The path of a local directory to upload as a configuration version and run in the target workspace. " +
+					"Exactly one of module_source or directory must be set.",
+				Optional: true,
+			},
+			"directory_hash": schema.StringAttribute{
+				MarkdownDescription: "A hash of the contents of the directory, used to detect changes to its files.",
+				Description:         "A hash of the contents of the directory, used to detect changes to its files.",
+				Computed:            true,
+			},
+			"configuration_version_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the configuration version uploaded from the directory, which is reused by the destroy run.",
+				Description:         "The ID of the configuration version uploaded from the directory, which is reused by the destroy run.",
+				Computed:            true,
 			},
 			"module_version": schema.StringAttribute{
 				MarkdownDescription: "The version identifier of the module.",
 				Description:         "The version identifier of the module.",
 				Optional:            true,
 				Computed:            true, // computed if not supplied
+				Validators: []validator.String{
+					validators.Semver(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -175,16 +326,128 @@ func (t *applyModuleResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Computed:            true,
 				Default:             booldefault.StaticBool(true),
 			},
-			"variables": schema.ListNestedAttribute{
-				MarkdownDescription: "Optional list of variables for the run in the target workspace.",
-				Description:         "Optional list of variables for the run in the target workspace.",
+			"refresh_only": schema.BoolAttribute{
+				MarkdownDescription: "Whether to only refresh the state of the target workspace, without changing any remote objects. Defaults to false.",
+				Description:         "Whether to only refresh the state of the target workspace, without changing any remote objects. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"target_addresses": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Optional list of resource addresses to limit the run to. The destroy run on delete is never limited.",
+				Description:         "Optional list of resource addresses to limit the run to. The destroy run on delete is never limited.",
+				Optional:            true,
+			},
+			"expected_module_digest": schema.StringAttribute{
+				MarkdownDescription: "Optional SHA-256 checksum, such as \"sha256:<hex>\", that the resolved version of a module " +
+					"from the Tharsis module registry must have. Otherwise, the run is canceled before it is applied.",
+				Description: "Optional SHA-256 checksum, such as \"sha256:<hex>\", that the resolved version of a module " +
+					"from the Tharsis module registry must have. Otherwise, the run is canceled before it is applied.",
+				Optional: true,
+			},
+			"triggers": schema.MapAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Optional map of arbitrary values that launch a new run in the target workspace whenever they change, " +
+					"even if nothing else has changed. The resource is updated, not replaced, so nothing is destroyed.",
+				Description: "Optional map of arbitrary values that launch a new run in the target workspace whenever they change, " +
+					"even if nothing else has changed. The resource is updated, not replaced, so nothing is destroyed.",
+				Optional: true,
+			},
+			"speculative": schema.BoolAttribute{
+				MarkdownDescription: "Whether to only do a speculative plan, reporting what would change without ever applying. Defaults to false.",
+				Description:         "Whether to only do a speculative plan, reporting what would change without ever applying. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"destroy_with_applied_variables": schema.BoolAttribute{
+				MarkdownDescription: "Whether the destroy run uses the variables, including environment variables, that were supplied to the latest apply. " +
+					"If false, the variables from the current state are used instead. Defaults to true.",
+				Description: "Whether the destroy run uses the variables, including environment variables, that were supplied to the latest apply. " +
+					"If false, the variables from the current state are used instead. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"destroy_on_delete": schema.BoolAttribute{
+				MarkdownDescription: "Whether deleting this resource does a destroy run in the workspace. If false, the resources " +
+					"that were applied are left in place, such as when handing a workspace over to another configuration. Defaults to true.",
+				Description: "Whether deleting this resource does a destroy run in the workspace. If false, the resources " +
+					"that were applied are left in place, such as when handing a workspace over to another configuration. Defaults to true.",
+				// Not computed with a default, so existing states don't plan a new run to set it.
+				Optional: true,
+			},
+			"auto_approve": schema.BoolAttribute{
+				MarkdownDescription: "Whether to apply each run as soon as its plan succeeds. If false, the run waits for " +
+					"someone to approve it in Tharsis, and an error naming the run is reported if it isn't approved " +
+					"before the timeout. Defaults to true.",
+				Description: "Whether to apply each run as soon as its plan succeeds. If false, the run waits for " +
+					"someone to approve it in Tharsis, and an error naming the run is reported if it isn't approved " +
+					"before the timeout. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"wait_for_completion": schema.BoolAttribute{
+				MarkdownDescription: "Whether to wait for the apply to finish. If false, the run is planned and applied but the apply " +
+					"isn't waited for, and later refreshes report the run's status. Destroy runs are always waited for. Defaults to true.",
+				Description: "Whether to wait for the apply to finish. If false, the run is planned and applied but the apply " +
+					"isn't waited for, and later refreshes report the run's status. Destroy runs are always waited for. Defaults to true.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The status of the latest run launched by this resource.",
+				Description:         "The status of the latest run launched by this resource.",
+				Computed:            true,
+			},
+			"detect_drift": schema.BoolAttribute{
+				MarkdownDescription: "Whether to do a speculative plan when refreshing, so that changes to the target workspace's " +
+					"resources cause this resource to be updated. Defaults to false.",
+				Description: "Whether to do a speculative plan when refreshing, so that changes to the target workspace's " +
+					"resources cause this resource to be updated. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"drift_detected": schema.BoolAttribute{
+				MarkdownDescription: "Whether the latest refresh detected drift in the target workspace. Only set if detect_drift is true.",
+				Description:         "Whether the latest refresh detected drift in the target workspace. Only set if detect_drift is true.",
+				Computed:            true,
+			},
+			"stream_logs": schema.BoolAttribute{
+				MarkdownDescription: "Whether to emit the logs of the plan and apply jobs to the provider's log while waiting for them. Defaults to false.",
+				Description:         "Whether to emit the logs of the plan and apply jobs to the provider's log while waiting for them. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"variables": schema.SetNestedAttribute{
+				MarkdownDescription: "Optional set of variables for the run in the target workspace. Each key may only be used once per category.",
+				Description:         "Optional set of variables for the run in the target workspace. Each key may only be used once per category.",
 				Optional:            true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"value": schema.StringAttribute{
-							MarkdownDescription: "Value of the variable.",
-							Description:         "Value of the variable.",
-							Required:            true,
+							MarkdownDescription: "Value of the variable. Values are always hidden from plan output. Conflicts with namespace_path.",
+							Description:         "Value of the variable. Values are always hidden from plan output. Conflicts with namespace_path.",
+							Optional:            true,
+							Sensitive:           true,
+						},
+						"namespace_path": schema.StringAttribute{
+							MarkdownDescription: "Full path of a group or workspace whose variable with the same key and category " +
+								"the run uses, rather than a value. Conflicts with value.",
+							Description: "Full path of a group or workspace whose variable with the same key and category " +
+								"the run uses, rather than a value. Conflicts with value.",
+							Optional: true,
+							Validators: []validator.String{
+								validators.RelativeResourcePath(),
+							},
 						},
 						"key": schema.StringAttribute{
 							MarkdownDescription: "Key or name of this variable.",
@@ -195,13 +458,32 @@ func (t *applyModuleResource) Schema(_ context.Context, _ resource.SchemaRequest
 							MarkdownDescription: "Category of this variable, 'terraform' or 'environment'.",
 							Description:         "Category of this variable, 'terraform' or 'environment'.",
 							Required:            true,
+							Validators: []validator.String{
+								validators.OneOf("terraform", "environment"),
+							},
+						},
+						"sensitive": schema.BoolAttribute{
+							MarkdownDescription: "Whether this variable is sensitive, in which case it is omitted from resolved_variables.",
+							Description:         "Whether this variable is sensitive, in which case it is omitted from resolved_variables.",
+							Optional:            true,
 						},
 					},
 				},
 			},
+			"environment_variables": schema.MapAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Optional map of environment variables for the run in the target workspace, a shorthand for " +
+					"variables in the 'environment' category. Values are always hidden from plan output. " +
+					"A key can't also be set as an environment variable in variables.",
+				Description: "Optional map of environment variables for the run in the target workspace, a shorthand for " +
+					"variables in the 'environment' category. Values are always hidden from plan output. " +
+					"A key can't also be set as an environment variable in variables.",
+				Optional:  true,
+				Sensitive: true,
+			},
 			"resolved_variables": schema.ListNestedAttribute{
-				MarkdownDescription: "The variables that were used by the run.",
-				Description:         "The variables that were used by the run.",
+				MarkdownDescription: "The variables that were used by the run, excluding those marked sensitive.",
+				Description:         "The variables that were used by the run, excluding those marked sensitive.",
 				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
@@ -228,10 +510,362 @@ func (t *applyModuleResource) Schema(_ context.Context, _ resource.SchemaRequest
 					},
 				},
 			},
+			"outputs": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The string outputs of the target workspace after the run. Outputs of other types are only available in outputs_json.",
+				Description:         "The string outputs of the target workspace after the run. Outputs of other types are only available in outputs_json.",
+				Computed:            true,
+			},
+			"outputs_json": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "All outputs of the target workspace after the run, JSON-encoded.",
+				Description:         "All outputs of the target workspace after the run, JSON-encoded.",
+				Computed:            true,
+			},
+			"run_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the latest run launched by this resource.",
+				Description:         "The ID of the latest run launched by this resource.",
+				Computed:            true,
+			},
+			"state_version_id": schema.StringAttribute{
+				MarkdownDescription: "The ID of the target workspace's current state version.",
+				Description:         "The ID of the target workspace's current state version.",
+				Computed:            true,
+			},
+			"resource_additions": schema.Int64Attribute{
+				MarkdownDescription: "The number of resources the latest run's plan added.",
+				Description:         "The number of resources the latest run's plan added.",
+				Computed:            true,
+			},
+			"resource_changes": schema.Int64Attribute{
+				MarkdownDescription: "The number of resources the latest run's plan changed.",
+				Description:         "The number of resources the latest run's plan changed.",
+				Computed:            true,
+			},
+			"resource_destructions": schema.Int64Attribute{
+				MarkdownDescription: "The number of resources the latest run's plan destroyed.",
+				Description:         "The number of resources the latest run's plan destroyed.",
+				Computed:            true,
+			},
+			"changes_detected": schema.BoolAttribute{
+				MarkdownDescription: "Whether the latest run's plan had any changes. It's false when the run finished after planning " +
+					"because there was nothing to apply, in which case the state version and outputs are kept as they were.",
+				Description: "Whether the latest run's plan had any changes. It's false when the run finished after planning " +
+					"because there was nothing to apply, in which case the state version and outputs are kept as they were.",
+				Computed: true,
+			},
+			"timeouts": schema.SingleNestedAttribute{
+				MarkdownDescription: "Optional timeouts for the runs launched by this resource.",
+				Description:         "Optional timeouts for the runs launched by this resource.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"create": schema.StringAttribute{
+						MarkdownDescription: "Timeout for the run launched on create, such as \"30m\" or \"2h\".",
+						Description:         "Timeout for the run launched on create, such as \"30m\" or \"2h\".",
+						Optional:            true,
+					},
+					"update": schema.StringAttribute{
+						MarkdownDescription: "Timeout for the run launched on update, such as \"30m\" or \"2h\".",
+						Description:         "Timeout for the run launched on update, such as \"30m\" or \"2h\".",
+						Optional:            true,
+					},
+					"delete": schema.StringAttribute{
+						MarkdownDescription: "Timeout for the destroy run launched on delete, such as \"30m\" or \"2h\".",
+						Description:         "Timeout for the destroy run launched on delete, such as \"30m\" or \"2h\".",
+						Optional:            true,
+					},
+				},
+			},
+			"retry": schema.SingleNestedAttribute{
+				MarkdownDescription: "Optional policy for retrying API calls that fail with transient errors. " +
+					"By default, calls are attempted 2 times, backing off from 1 second up to 10 seconds. " +
+					"This is on top of the Tharsis SDK, which already retries each request 3 times.",
+				Description: "Optional policy for retrying API calls that fail with transient errors. " +
+					"By default, calls are attempted 2 times, backing off from 1 second up to 10 seconds. " +
+					"This is on top of the Tharsis SDK, which already retries each request 3 times.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						MarkdownDescription: "The maximum number of attempts of each call, including the first. 1 disables retries.",
+						Description:         "The maximum number of attempts of each call, including the first. 1 disables retries.",
+						Optional:            true,
+					},
+					"initial_backoff": schema.StringAttribute{
+						MarkdownDescription: "The delay before the first retry, such as \"1s\". The delay doubles after each retry.",
+						Description:         "The delay before the first retry, such as \"1s\". The delay doubles after each retry.",
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: "The maximum delay between retries, such as \"30s\".",
+						Description:         "The maximum delay between retries, such as \"30s\".",
+						Optional:            true,
+					},
+					"retry_on": schema.ListAttribute{
+						MarkdownDescription: "The classes of errors to retry: server_error (service unavailable errors), " +
+							"rate_limit (too many requests errors) and network. Defaults to all of them.",
+						Description: "The classes of errors to retry: server_error (service unavailable errors), " +
+							"rate_limit (too many requests errors) and network. Defaults to all of them.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+			"job_poll_interval": schema.StringAttribute{
+				MarkdownDescription: "How often to check whether a plan or apply job has finished, such as \"10s\". Defaults to 5 seconds.",
+				Description:         "How often to check whether a plan or apply job has finished, such as \"10s\". Defaults to 5 seconds.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that the timeouts and poll interval are valid durations.
+func (t *applyModuleResource) ValidateConfig(ctx context.Context,
+	req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse,
+) {
+	var config ApplyModuleModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Exactly one of the module source or directory must be set.
+	if !config.ModuleSource.IsUnknown() && !config.Directory.IsUnknown() {
+		switch {
+		case config.ModuleSource.IsNull() && config.Directory.IsNull():
+			resp.Diagnostics.AddError("Missing module source or directory",
+				"Exactly one of module_source or directory must be set.",
+			)
+		case !config.ModuleSource.IsNull() && !config.Directory.IsNull():
+			resp.Diagnostics.AddAttributeError(path.Root("directory"),
+				"Conflicting module source and directory",
+				"Exactly one of module_source or directory must be set.",
+			)
+		}
+	}
+	if !config.Directory.IsNull() && !config.ModuleVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("module_version"),
+			"Module version cannot be used with directory",
+			"A module version can only be set along with module_source.",
+		)
+	}
+	if !config.Directory.IsNull() && !config.ExpectedModuleDigest.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("expected_module_digest"),
+			"Expected module digest cannot be used with directory",
+			"A module digest can only be verified along with module_source.",
+		)
+	}
+	if !config.ExpectedModuleDigest.IsNull() && !config.ExpectedModuleDigest.IsUnknown() {
+		digest := normalizeDigest(config.ExpectedModuleDigest.ValueString())
+		if _, err := hex.DecodeString(digest); err != nil || len(digest) != sha256.Size*2 {
+			resp.Diagnostics.AddAttributeError(path.Root("expected_module_digest"),
+				"Invalid module digest",
+				fmt.Sprintf("Expected a SHA-256 checksum such as \"sha256:<hex>\", got: %s", config.ExpectedModuleDigest.ValueString()),
+			)
+		}
+	}
+
+	// Each variable must be uniquely identified by its category and key.
+	seen := map[string]bool{}
+	if !config.Variables.IsUnknown() {
+		for _, element := range config.Variables.Elements() {
+			// Skip any variables that aren't fully known yet.
+			variable, ok := element.(types.Object)
+			if !ok || variable.IsUnknown() {
+				continue
+			}
+			key, keyOK := variable.Attributes()["key"].(types.String)
+			category, categoryOK := variable.Attributes()["category"].(types.String)
+			if !keyOK || !categoryOK || key.IsUnknown() || category.IsUnknown() {
+				continue
+			}
+
+			id := category.ValueString() + "/" + key.ValueString()
+			if seen[id] {
+				resp.Diagnostics.AddAttributeError(path.Root("variables"),
+					"Duplicate variable",
+					fmt.Sprintf("Variable %s in category %s is defined more than once.", key.ValueString(), category.ValueString()),
+				)
+			}
+			seen[id] = true
+
+			// A variable either has a value or refers to a namespace's variable.
+			value, valueOK := variable.Attributes()["value"].(types.String)
+			namespacePath, namespacePathOK := variable.Attributes()["namespace_path"].(types.String)
+			if !valueOK || !namespacePathOK || value.IsUnknown() || namespacePath.IsUnknown() {
+				continue
+			}
+			if value.IsNull() == namespacePath.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("variables"),
+					"Invalid variable",
+					fmt.Sprintf("Exactly one of value or namespace_path must be set for variable %s in category %s.",
+						key.ValueString(), category.ValueString()),
+				)
+			}
+		}
+	}
+
+	// The environment variables map can't repeat an environment variable from the variables.
+	if !config.EnvironmentVariables.IsUnknown() {
+		for key := range config.EnvironmentVariables.Elements() {
+			if seen[string(sdktypes.EnvironmentVariableCategory)+"/"+key] {
+				resp.Diagnostics.AddAttributeError(path.Root("environment_variables").AtMapKey(key),
+					"Duplicate variable",
+					fmt.Sprintf("Environment variable %s is set in both variables and environment_variables.", key),
+				)
+			}
+		}
+	}
+
+	if !config.JobPollInterval.IsNull() && !config.JobPollInterval.IsUnknown() {
+		interval, err := time.ParseDuration(config.JobPollInterval.ValueString())
+		if err != nil || interval <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("job_poll_interval"),
+				"Invalid job poll interval",
+				fmt.Sprintf("Expected a positive duration such as \"10s\", got: %s", config.JobPollInterval.ValueString()),
+			)
+		}
+	}
+
+	if !config.Retry.IsNull() && !config.Retry.IsUnknown() {
+		var retry ApplyModuleRetryModel
+		resp.Diagnostics.Append(config.Retry.As(ctx, &retry, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if !retry.MaxAttempts.IsNull() && !retry.MaxAttempts.IsUnknown() && retry.MaxAttempts.ValueInt64() < 1 {
+			resp.Diagnostics.AddAttributeError(path.Root("retry").AtName("max_attempts"),
+				"Invalid maximum attempts",
+				fmt.Sprintf("Expected at least 1, got: %d", retry.MaxAttempts.ValueInt64()),
+			)
+		}
+
+		for name, value := range map[string]types.String{
+			"initial_backoff": retry.InitialBackoff,
+			"max_backoff":     retry.MaxBackoff,
+		} {
+			if value.IsNull() || value.IsUnknown() {
+				continue
+			}
+			if backoff, err := time.ParseDuration(value.ValueString()); err != nil || backoff <= 0 {
+				resp.Diagnostics.AddAttributeError(path.Root("retry").AtName(name),
+					"Invalid backoff",
+					fmt.Sprintf("Expected a positive duration such as \"5s\", got: %s", value.ValueString()),
+				)
+			}
+		}
+
+		if !retry.RetryOn.IsUnknown() {
+			for _, element := range retry.RetryOn.Elements() {
+				class, ok := element.(types.String)
+				if !ok || class.IsUnknown() {
+					continue
+				}
+				if !slices.Contains(retryClasses, class.ValueString()) {
+					resp.Diagnostics.AddAttributeError(path.Root("retry").AtName("retry_on"),
+						"Invalid error class",
+						fmt.Sprintf("Expected one of %s, got: %s", strings.Join(retryClasses, ", "), class.ValueString()),
+					)
+				}
+			}
+		}
+	}
+
+	if config.Timeouts.IsNull() || config.Timeouts.IsUnknown() {
+		return
+	}
+
+	var timeouts ApplyModuleTimeoutsModel
+	resp.Diagnostics.Append(config.Timeouts.As(ctx, &timeouts, basetypes.ObjectAsOptions{})...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name, value := range map[string]types.String{
+		"create": timeouts.Create,
+		"update": timeouts.Update,
+		"delete": timeouts.Delete,
+	} {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		if timeout, err := time.ParseDuration(value.ValueString()); err != nil || timeout <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("timeouts").AtName(name),
+				"Invalid timeout",
+				fmt.Sprintf("Expected a positive duration such as \"30m\", got: %s", value.ValueString()),
+			)
+		}
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *applyModuleResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 1 stored the variables as a list rather than a set.
+		1: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				// Lists and sets have the same JSON representation, so the
+				// prior state can be read directly with the current schema.
+				upgraded, err := req.RawState.Unmarshal(resp.State.Schema.Type().TerraformType(ctx))
+				if err != nil {
+					resp.Diagnostics.AddError("Failed to upgrade tharsis_apply_module state", err.Error())
+					return
+				}
+
+				resp.State.Raw = upgraded
+			},
 		},
 	}
 }
 
+// ModifyPlan forces an update if the directory's files changed, drift was detected while refreshing, or the latest run errored.
+func (t *applyModuleResource) ModifyPlan(ctx context.Context,
+	req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse,
+) {
+	// Nothing to do on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ApplyModuleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Hashing the directory lets changes to its files be planned.
+	if !plan.Directory.IsNull() && !plan.Directory.IsUnknown() {
+		hash, err := hashDirectory(plan.Directory.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("directory"), "Failed to read directory", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("directory_hash"), types.StringValue(hash))...)
+	}
+
+	// Nothing else to do on create.
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state ApplyModuleModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DriftDetected.ValueBool() {
+		// The new run will converge the target workspace.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("drift_detected"), types.BoolValue(false))...)
+	}
+
+	if state.Status.ValueString() == string(sdktypes.RunErrored) {
+		// The latest run errored, so launch a new one, whose status isn't known yet.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("status"), types.StringUnknown())...)
+	}
+}
+
 // Configure lets the provider implement the ResourceWithConfigure interface.
 func (t *applyModuleResource) Configure(_ context.Context,
 	req resource.ConfigureRequest, _ *resource.ConfigureResponse,
@@ -239,7 +873,120 @@ func (t *applyModuleResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = newApplyModuleClient(p.client)
+	t.runLimiter = p.runLimiter
+	t.paths = p.paths
+	t.workspaces = p.workspaces
+}
+
+// importedVariable is a variable supplied to an imported run, in the form of the variables attribute.
+type importedVariable struct {
+	Value         string       `tfsdk:"value"`
+	NamespacePath types.String `tfsdk:"namespace_path"`
+	Key           string       `tfsdk:"key"`
+	Category      string       `tfsdk:"category"`
+	Sensitive     types.Bool   `tfsdk:"sensitive"`
+}
+
+// ImportState adopts the module currently applied to the workspace whose full path is the import ID.
+func (t *applyModuleResource) ImportState(ctx context.Context,
+	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
+) {
+	currentApplied, newDiags := t.getCurrentApplied(ctx, t.client.Workspaces, ApplyModuleModel{
+		WorkspacePath: types.StringValue(req.ID),
+	})
+	resp.Diagnostics.Append(newDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only a module applied by a run can be adopted.
+	switch {
+	case currentApplied == nil, currentApplied.wasSuccessfulDestroy:
+		resp.Diagnostics.AddError("Nothing to import", fmt.Sprintf("No module is currently applied to workspace %s.", req.ID))
+		return
+	case currentApplied.wasManualUpdate:
+		resp.Diagnostics.AddError("Cannot import manually updated state",
+			fmt.Sprintf("The current state of workspace %s was not created by a run.", req.ID))
+		return
+	case currentApplied.moduleSource == nil:
+		resp.Diagnostics.AddError("Cannot import configuration version",
+			fmt.Sprintf("The current state of workspace %s was not created from a module source.", req.ID))
+		return
+	}
+
+	runID := currentApplied.stateVersion.RunID
+	run, err := t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: runID})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Failed to get latest run", err, req.ID))
+		return
+	}
+
+	runVars, err := t.client.Run.GetRunVariables(ctx, &sdktypes.GetRunInput{ID: runID})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Failed to get resolved variables", err, req.ID))
+		return
+	}
+
+	// The variables supplied to the run, as opposed to those inherited from a namespace, become the variables attribute.
+	var variables []importedVariable
+	for _, variable := range runVars {
+		if variable.NamespacePath == nil && variable.Value != nil {
+			variables = append(variables, importedVariable{
+				Value:         *variable.Value,
+				NamespacePath: types.StringNull(),
+				Key:           variable.Key,
+				Category:      string(variable.Category),
+				Sensitive:     types.BoolNull(),
+			})
+		}
+	}
+
+	resolvedVars, newDiags := t.toProviderOutputVariables(ctx, runVars, &basetypes.SetValue{})
+	resp.Diagnostics.Append(newDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keep the applied variables for the destroy run.
+	appliedVars, err := t.encodeAppliedVariables(runVars)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to encode applied variables", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, appliedVariablesPrivateStateKey, appliedVars)...)
+
+	// Set the attributes with defaults too, so the next plan doesn't launch a run just to set them.
+	// Read fills in the state version and outputs.
+	for name, value := range map[string]any{
+		"id":                             uuid.New().String(),
+		"workspace_path":                 req.ID,
+		"module_source":                  *currentApplied.moduleSource,
+		"module_version":                 currentApplied.moduleVersion,
+		"variables":                      variables,
+		"resolved_variables":             resolvedVars,
+		"run_id":                         runID,
+		"status":                         string(run.Status),
+		"refresh":                        true,
+		"refresh_only":                   false,
+		"speculative":                    false,
+		"detect_drift":                   false,
+		"drift_detected":                 false,
+		"auto_approve":                   true,
+		"wait_for_completion":            true,
+		"stream_logs":                    false,
+		"destroy_with_applied_variables": true,
+	} {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(name), value)...)
+	}
+
+	if run.Plan != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource_additions"), int64(run.Plan.ResourceAdditions))...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource_changes"), int64(run.Plan.ResourceChanges))...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource_destructions"), int64(run.Plan.ResourceDestructions))...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("changes_detected"), run.Plan.HasChanges)...)
+	}
 }
 
 func (t *applyModuleResource) Create(ctx context.Context,
@@ -252,9 +999,19 @@ func (t *applyModuleResource) Create(ctx context.Context,
 		return
 	}
 
+	ctx, cancel, newDiags := t.withTimeout(ctx, &applyModule, "create")
+	resp.Diagnostics.Append(newDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	// Do plan and apply, no destroy.
 	didRun, newDiags := t.createRun(ctx, &createRunInput{
-		model: &applyModule,
+		model:          &applyModule,
+		pollInterval:   t.getPollInterval(&applyModule),
+		streamLogs:     applyModule.StreamLogs.ValueBool(),
+		noWaitForApply: !applyModule.WaitForCompletion.ValueBool(),
 	})
 	resp.Diagnostics.Append(newDiags...)
 	if resp.Diagnostics.HasError() {
@@ -262,7 +1019,7 @@ func (t *applyModuleResource) Create(ctx context.Context,
 	}
 
 	// Transform the resolved variables from the run.
-	resolvedVars, diags := t.toProviderOutputVariables(ctx, didRun.resolvedVariables)
+	resolvedVars, diags := t.toProviderOutputVariables(ctx, didRun.resolvedVariables, &applyModule.Variables)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
@@ -270,8 +1027,22 @@ func (t *applyModuleResource) Create(ctx context.Context,
 
 	// Update the plan with the computed ID.
 	applyModule.ID = types.StringValue(uuid.New().String())
-	applyModule.ModuleVersion = types.StringValue(didRun.moduleVersion)
 	applyModule.ResolvedVariables = resolvedVars
+	applyModule.DriftDetected = types.BoolValue(false)
+	t.copyRunSummary(didRun, &applyModule)
+
+	// Keep the applied variables for the destroy run.
+	if !applyModule.Speculative.ValueBool() {
+		appliedVars, err := t.encodeAppliedVariables(didRun.resolvedVariables)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encode applied variables", err.Error())
+		} else {
+			resp.Diagnostics.Append(resp.Private.SetKey(ctx, appliedVariablesPrivateStateKey, appliedVars)...)
+		}
+	}
+
+	// Capture the state version and outputs of the target workspace.
+	resp.Diagnostics.Append(t.getCurrentStateVersion(ctx, &applyModule)...)
 
 	// Set the response state to the fully-populated plan, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, applyModule)...)
@@ -287,7 +1058,13 @@ func (t *applyModuleResource) Read(ctx context.Context,
 		return
 	}
 
-	currentApplied, newDiags := t.getCurrentApplied(ctx, state)
+	// A speculative plan never changes the workspace, so there is nothing to refresh.
+	if state.Speculative.ValueBool() {
+		return
+	}
+
+	// The workspace may have been read by other resources in this refresh.
+	currentApplied, newDiags := t.getCurrentApplied(ctx, t.refreshWorkspaces(), state)
 	resp.Diagnostics.Append(newDiags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -306,6 +1083,56 @@ func (t *applyModuleResource) Read(ctx context.Context,
 		state.ModuleVersion = types.StringNull()
 	}
 
+	// Refresh the state version ID and outputs from the current state version.
+	var stateVersion *sdktypes.StateVersion
+	if currentApplied != nil {
+		stateVersion = currentApplied.stateVersion
+	}
+	resp.Diagnostics.Append(t.setStateVersion(ctx, &state, stateVersion)...)
+
+	// Report the latest status of the run, which may not have finished when it was launched.
+	if !state.RunID.IsNull() && !state.RunID.IsUnknown() {
+		run, err := t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: state.RunID.ValueString()})
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Failed to get latest run", err, state.WorkspacePath.ValueString()))
+			return
+		}
+
+		state.Status = types.StringValue(string(run.Status))
+		if run.Status == sdktypes.RunErrored {
+			resp.Diagnostics.AddWarning(
+				fmt.Sprintf("Run %s in workspace %s errored", state.RunID.ValueString(), state.WorkspacePath.ValueString()),
+				"The next apply will launch a new run.",
+			)
+		}
+	}
+
+	// Check whether the target workspace has drifted, but only if the module or directory is still applied.
+	if state.DetectDrift.ValueBool() && (!state.ModuleSource.IsNull() ||
+		(!state.Directory.IsNull() && currentApplied != nil && !currentApplied.wasSuccessfulDestroy)) {
+		didRun, newDiags := t.createRun(ctx, &createRunInput{
+			model:          &state,
+			pollInterval:   t.getPollInterval(&state),
+			streamLogs:     state.StreamLogs.ValueBool(),
+			detectingDrift: true,
+		})
+
+		// Failing to detect drift shouldn't prevent planning.
+		for _, d := range newDiags {
+			if d.Severity() == diag.SeverityError {
+				resp.Diagnostics.AddWarning("Failed to detect drift: "+d.Summary(), d.Detail())
+			} else {
+				resp.Diagnostics.Append(d)
+			}
+		}
+
+		if didRun != nil {
+			state.DriftDetected = types.BoolValue(
+				(didRun.resourceAdditions + didRun.resourceChanges + didRun.resourceDestructions) > 0,
+			)
+		}
+	}
+
 	// Don't try to set the resolved variables in the Read method, because the run has not yet been done.
 
 	// Set the refreshed state, whether or not there is an error.
@@ -322,28 +1149,59 @@ func (t *applyModuleResource) Update(ctx context.Context,
 		return
 	}
 
+	ctx, cancel, newDiags := t.withTimeout(ctx, &plan, "update")
+	resp.Diagnostics.Append(newDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
 	// Do the run.
 	didRun, newDiags := t.createRun(ctx, &createRunInput{
-		model: &plan,
+		model:          &plan,
+		pollInterval:   t.getPollInterval(&plan),
+		streamLogs:     plan.StreamLogs.ValueBool(),
+		noWaitForApply: !plan.WaitForCompletion.ValueBool(),
 	})
 	resp.Diagnostics.Append(newDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Capture the module version in case it changed.
-	plan.ModuleVersion = types.StringValue(didRun.moduleVersion)
-
 	// Transform the resolved variables from the run.
-	resolvedVars, diags := t.toProviderOutputVariables(ctx, didRun.resolvedVariables)
+	resolvedVars, diags := t.toProviderOutputVariables(ctx, didRun.resolvedVariables, &plan.Variables)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 	plan.ResolvedVariables = resolvedVars
+	plan.DriftDetected = types.BoolValue(false)
+	t.copyRunSummary(didRun, &plan)
 
-	// Set the response state to the fully-populated plan, with or without error.
-	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	// Keep the applied variables for the destroy run.
+	if !plan.Speculative.ValueBool() {
+		appliedVars, err := t.encodeAppliedVariables(didRun.resolvedVariables)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to encode applied variables", err.Error())
+		} else {
+			resp.Diagnostics.Append(resp.Private.SetKey(ctx, appliedVariablesPrivateStateKey, appliedVars)...)
+		}
+	}
+
+	// A run without changes leaves the workspace as it was, so keep the state version and outputs
+	// rather than reading them again.  Otherwise, capture those of the target workspace.
+	if !didRun.changesDetected && !plan.Speculative.ValueBool() {
+		var state ApplyModuleModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		plan.StateVersionID = state.StateVersionID
+		plan.Outputs = state.Outputs
+		plan.OutputsJSON = state.OutputsJSON
+	} else {
+		resp.Diagnostics.Append(t.getCurrentStateVersion(ctx, &plan)...)
+	}
+
+	// Set the response state to the fully-populated plan, with or without error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 func (t *applyModuleResource) Delete(ctx context.Context,
@@ -356,7 +1214,27 @@ func (t *applyModuleResource) Delete(ctx context.Context,
 		return
 	}
 
-	currentApplied, newDiags := t.getCurrentApplied(ctx, state)
+	// A speculative plan never applied anything, so there is nothing to destroy.
+	if state.Speculative.ValueBool() {
+		return
+	}
+
+	// The applied resources are left in place if asked.
+	if !state.DestroyOnDelete.IsNull() && !state.DestroyOnDelete.ValueBool() {
+		tflog.Info(ctx, "Leaving the applied resources in place", map[string]any{
+			"workspace_path": state.WorkspacePath.ValueString(),
+		})
+		return
+	}
+
+	ctx, cancel, newDiags := t.withTimeout(ctx, &state, "delete")
+	resp.Diagnostics.Append(newDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	defer cancel()
+
+	currentApplied, newDiags := t.getCurrentApplied(ctx, t.client.Workspaces, state)
 	resp.Diagnostics.Append(newDiags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -399,229 +1277,868 @@ func (t *applyModuleResource) Delete(ctx context.Context,
 		}
 	}
 
-	// The apply module is being deleted, so don't use the module version output.
-	didRun, newDiags2 := t.createRun(ctx, &createRunInput{
-		model:     &state,
-		doDestroy: true,
+	// Replay the variables from the latest apply, if they were kept.
+	// Older states won't have them, in which case the variables from state are used.
+	var destroyVars []sdktypes.RunVariable
+	if state.DestroyWithAppliedVariables.ValueBool() {
+		appliedVars, getDiags := req.Private.GetKey(ctx, appliedVariablesPrivateStateKey)
+		resp.Diagnostics.Append(getDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if appliedVars != nil {
+			var err error
+			if destroyVars, err = t.decodeAppliedVariables(appliedVars); err != nil {
+				resp.Diagnostics.AddError("Failed to decode applied variables", err.Error())
+				return
+			}
+		}
+	}
+
+	// The apply module is being deleted, so don't use the module version output.
+	didRun, newDiags2 := t.createRun(ctx, &createRunInput{
+		variables:    destroyVars,
+		model:        &state,
+		doDestroy:    true,
+		pollInterval: t.getPollInterval(&state),
+		streamLogs:   state.StreamLogs.ValueBool(),
+	})
+	resp.Diagnostics.Append(newDiags2...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Transform the resolved variables from the destroy run.
+	resolvedVars, diags := t.toProviderOutputVariables(ctx, didRun.resolvedVariables, &state.Variables)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	state.ResolvedVariables = resolvedVars
+
+	// Set the response state to be fully-populated, with or without error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// createRun launches a remote run and waits for it to complete.
+func (t *applyModuleResource) createRun(ctx context.Context, input *createRunInput) (*createRunOutput, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	retry, retryDiags := t.getRetryPolicy(ctx, input.model)
+	diags.Append(retryDiags...)
+	if diags.HasError() {
+		return nil, diags
+	}
+	input.retry = retry
+
+	// Wait for a slot if the provider limits concurrent runs.
+	release, err := t.runLimiter.acquire(ctx, map[string]any{"workspace_path": input.model.WorkspacePath.ValueString()})
+	if err != nil {
+		diags.AddError("Failed to wait for another run to finish", err.Error())
+		return nil, diags
+	}
+	defer release()
+
+	// Convert the input variables, unless they were supplied.
+	vars := input.variables
+	if vars == nil {
+		vars, err = t.copyRunVariablesToInput(ctx, &input.model.Variables, input.model.EnvironmentVariables)
+		if err != nil {
+			diags.AddError("Failed to convert variables to SDK types", err.Error())
+			return nil, diags
+		}
+	}
+
+	// The destroy run must destroy everything, so it's never limited.
+	var targetAddresses []string
+	if !input.doDestroy {
+		diags.Append(input.model.TargetAddresses.ElementsAs(ctx, &targetAddresses, false)...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	// A directory is run from a configuration version rather than a module source.
+	// The destroy run reuses the last configuration version, in case the directory is gone.
+	var moduleSource, moduleVersion, configurationVersionID *string
+	if !input.model.Directory.IsNull() {
+		if input.doDestroy && !input.model.ConfigurationVersionID.IsNull() {
+			configurationVersionID = ptr.String(input.model.ConfigurationVersionID.ValueString())
+		} else {
+			id, err := t.uploadConfigurationVersion(ctx, input)
+			if err != nil {
+				diags.Append(apiErrorDiagnostic("Failed to upload configuration version", err, input.model.WorkspacePath.ValueString()))
+				return nil, diags
+			}
+			configurationVersionID = &id
+		}
+	} else {
+		moduleSource = ptr.String(input.model.ModuleSource.ValueString())
+		if !input.model.ModuleVersion.IsUnknown() {
+			moduleVersion = ptr.String(input.model.ModuleVersion.ValueString())
+		}
+	}
+
+	// Call CreateRun
+	var createdRun *sdktypes.Run
+	err = withRetry(ctx, input.retry, "CreateRun", func() (err error) {
+		createdRun, err = t.client.Run.CreateRun(ctx, &sdktypes.CreateRunInput{
+			WorkspacePath:          t.paths.resolve(input.model.WorkspacePath.ValueString()),
+			IsDestroy:              input.doDestroy,
+			ModuleSource:           moduleSource,
+			ModuleVersion:          moduleVersion,
+			ConfigurationVersionID: configurationVersionID,
+			Refresh:                input.model.Refresh.ValueBool(),
+			Variables:              vars,
+			Speculative:            ptr.Bool((input.model.Speculative.ValueBool() || input.detectingDrift) && !input.doDestroy),
+			RefreshOnly:            input.model.RefreshOnly.ValueBool() && !input.doDestroy,
+			TargetAddresses:        targetAddresses,
+		})
+		return err
+	})
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Failed to create run", err, input.model.WorkspacePath.ValueString()))
+		return nil, diags
+	}
+
+	if err = t.waitForJobCompletion(ctx, createdRun.Plan.CurrentJobID, input); err != nil {
+		diags.Append(apiErrorDiagnostic("Failed to wait for plan job completion", err, input.model.WorkspacePath.ValueString()))
+		if ctx.Err() != nil {
+			// Terraform was interrupted or timed out, so don't leave the run going.
+			diags.Append(t.cancelRun(createdRun.Metadata.ID)...)
+		}
+		return nil, diags
+	}
+
+	var plannedRun *sdktypes.Run
+	err = withRetry(ctx, input.retry, "GetRun", func() (err error) {
+		plannedRun, err = t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: createdRun.Metadata.ID})
+		return err
+	})
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Failed to get planned run", err, input.model.WorkspacePath.ValueString()))
+		return nil, diags
+	}
+
+	// If the plan fails, both plannedRun.Status and plannedRun.Plan.Status are "errored".
+	// If the plan succeeds, plannedRun.Status is "planned",
+	// while plannedRun.Plan.Status is "finished".
+	//
+	switch plannedRun.Plan.Status {
+	case sdktypes.PlanCanceled:
+		diags.AddError("Plan was canceled", string(plannedRun.Plan.Status))
+		return nil, diags
+	case sdktypes.PlanErrored:
+		// Bring in any error message(s) from the finished inner plan run.
+		innerPlanRunDiags := t.extractRunError(ctx, input.retry, plannedRun)
+		if innerPlanRunDiags.HasError() {
+			diags.Append(innerPlanRunDiags...)
+		} else {
+			diags.AddError("Plan failed with unknown error", string(plannedRun.Plan.Status))
+		}
+		return nil, diags
+	}
+
+	// Capture the run ID.
+	runID := plannedRun.Metadata.ID
+
+	// Get the resolved variables from the run.
+	var resolvedPlanVars []sdktypes.RunVariable
+	err = withRetry(ctx, input.retry, "GetRunVariables", func() (err error) {
+		resolvedPlanVars, err = t.client.Run.GetRunVariables(ctx, &sdktypes.GetRunInput{ID: runID})
+		return err
+	})
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Failed to get resolved variables", err, input.model.WorkspacePath.ValueString()))
+		return nil, diags
+	}
+
+	// A speculative run ends after the plan, so only report what would have changed.
+	if input.model.Speculative.ValueBool() && !input.detectingDrift {
+		diags.AddWarning(
+			fmt.Sprintf("Speculative plan for workspace %s", input.model.WorkspacePath.ValueString()),
+			fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.",
+				plannedRun.Plan.ResourceAdditions, plannedRun.Plan.ResourceChanges, plannedRun.Plan.ResourceDestructions,
+			),
+		)
+	}
+
+	if (plannedRun.Status == sdktypes.RunPlannedAndFinished) && !input.model.Speculative.ValueBool() && !input.detectingDrift {
+		tflog.Info(ctx, "Run finished after planning because there are no changes to apply", map[string]any{
+			"run_id":         runID,
+			"workspace_path": input.model.WorkspacePath.ValueString(),
+		})
+	}
+
+	// Never apply a speculative run, whatever its status.
+	if (plannedRun.Status == sdktypes.RunPlannedAndFinished) || input.model.Speculative.ValueBool() || input.detectingDrift {
+		result := &createRunOutput{
+			runID:                  runID,
+			status:                 string(plannedRun.Status),
+			configurationVersionID: ptr.ToString(configurationVersionID),
+			resolvedVariables:      resolvedPlanVars,
+			resourceAdditions:      int64(plannedRun.Plan.ResourceAdditions),
+			resourceChanges:        int64(plannedRun.Plan.ResourceChanges),
+			resourceDestructions:   int64(plannedRun.Plan.ResourceDestructions),
+			changesDetected:        plannedRun.Plan.HasChanges,
+		}
+
+		if plannedRun.ModuleVersion != nil {
+			result.moduleVersion = *plannedRun.ModuleVersion
+		}
+		return result, diags
+	}
+
+	// Verify the resolved module before anything is applied.
+	if !input.model.ExpectedModuleDigest.IsNull() {
+		if err = verifyModuleDigest(plannedRun, input.model.ExpectedModuleDigest.ValueString()); err != nil {
+			diags.AddError("Failed to verify module digest", err.Error())
+			diags.Append(t.cancelRun(runID)...)
+			return nil, diags
+		}
+	}
+
+	// Do the apply run, unless it must be approved by someone else.
+	var appliedRun *sdktypes.Run
+	if input.model.AutoApprove.ValueBool() {
+		err = withRetry(ctx, input.retry, "ApplyRun", func() (err error) {
+			appliedRun, err = t.client.Run.ApplyRun(ctx, &sdktypes.ApplyRunInput{
+				RunID:   runID,
+				Comment: &applyRunComment,
+			})
+			return err
+		})
+		if err != nil {
+			diags.Append(apiErrorDiagnostic("Failed to apply a run", err, input.model.WorkspacePath.ValueString()))
+			return nil, diags
+		}
+	} else {
+		var approvalDiags diag.Diagnostics
+		appliedRun, approvalDiags = t.waitForApproval(ctx, input, plannedRun)
+		diags.Append(approvalDiags...)
+		if diags.HasError() {
+			return nil, diags
+		}
+	}
+
+	// The apply changes the workspace, so it has to be read again.
+	t.workspaces.forget(&sdktypes.GetWorkspaceInput{
+		Path: ptr.String(t.paths.resolve(input.model.WorkspacePath.ValueString())),
+	})
+
+	// Make sure the run has an apply.
+	if appliedRun.Apply == nil {
+		msg := fmt.Sprintf("Created run does not have an apply: %s", appliedRun.Metadata.ID)
+		diags.AddError(msg, "")
+		return nil, diags
+	}
+
+	// The apply has started, so there's nothing else to do if not waiting for it.
+	if input.noWaitForApply {
+		result := &createRunOutput{
+			runID:                  runID,
+			status:                 string(appliedRun.Status),
+			configurationVersionID: ptr.ToString(configurationVersionID),
+			resolvedVariables:      resolvedPlanVars,
+			resourceAdditions:      int64(plannedRun.Plan.ResourceAdditions),
+			resourceChanges:        int64(plannedRun.Plan.ResourceChanges),
+			resourceDestructions:   int64(plannedRun.Plan.ResourceDestructions),
+			changesDetected:        plannedRun.Plan.HasChanges,
+		}
+
+		if plannedRun.ModuleVersion != nil {
+			result.moduleVersion = *plannedRun.ModuleVersion
+		}
+		return result, diags
+	}
+
+	if err = t.waitForJobCompletion(ctx, appliedRun.Apply.CurrentJobID, input); err != nil {
+		diags.Append(apiErrorDiagnostic("Failed to wait for apply job completion", err, input.model.WorkspacePath.ValueString()))
+		if ctx.Err() != nil {
+			// Terraform was interrupted or timed out, so don't leave the run going.
+			diags.Append(t.cancelRun(appliedRun.Metadata.ID)...)
+		}
+		return nil, diags
+	}
+
+	var finishedRun *sdktypes.Run
+	err = withRetry(ctx, input.retry, "GetRun", func() (err error) {
+		finishedRun, err = t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: appliedRun.Metadata.ID})
+		return err
+	})
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Failed to get finished run", err, input.model.WorkspacePath.ValueString()))
+		return nil, diags
+	}
+
+	// If an apply job succeeds, finishedRun.Status is "applied" and
+	// finishedRun.Apply.Status is "finished".
+	switch finishedRun.Apply.Status {
+	case sdktypes.ApplyCanceled:
+		diags.AddError("Apply was canceled", string(finishedRun.Apply.Status))
+		return nil, diags
+	case sdktypes.ApplyErrored:
+		// Bring in any error message(s) from the finished inner apply run.
+		innerApplyRunDiags := t.extractRunError(ctx, input.retry, finishedRun)
+		if innerApplyRunDiags.HasError() {
+			diags.Append(innerApplyRunDiags...)
+		} else {
+			diags.AddError("Apply failed with unknown error", string(finishedRun.Apply.Status))
+		}
+		return nil, diags
+	}
+
+	// In case of a rainy day, make sure the ModuleSource and ModuleVersion *string aren't nil.
+	// Runs of a configuration version have neither.
+	if configurationVersionID == nil {
+		if finishedRun.ModuleSource == nil {
+			diags.AddError("Finished run's module source is nil.", "")
+			return nil, diags
+		}
+		if finishedRun.ModuleVersion == nil {
+			diags.AddError("Finished run's module version is nil.", "")
+			return nil, diags
+		}
+	}
+
+	// Get the resolved variables from the run.
+	var resolvedApplyVars []sdktypes.RunVariable
+	err = withRetry(ctx, input.retry, "GetRunVariables", func() (err error) {
+		resolvedApplyVars, err = t.client.Run.GetRunVariables(ctx, &sdktypes.GetRunInput{ID: finishedRun.Metadata.ID})
+		return err
+	})
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Failed to get resolved variables", err, input.model.WorkspacePath.ValueString()))
+		return nil, diags
+	}
+
+	// The module version was checked above, so it's safe to dereference.
+	// These diags may include those from the inner run if it errored out.
+	return &createRunOutput{
+		runID:                  runID,
+		status:                 string(finishedRun.Status),
+		configurationVersionID: ptr.ToString(configurationVersionID),
+		resolvedVariables:      resolvedApplyVars,
+		moduleVersion:          ptr.ToString(finishedRun.ModuleVersion),
+		resourceAdditions:      int64(plannedRun.Plan.ResourceAdditions),
+		resourceChanges:        int64(plannedRun.Plan.ResourceChanges),
+		resourceDestructions:   int64(plannedRun.Plan.ResourceDestructions),
+		changesDetected:        plannedRun.Plan.HasChanges,
+	}, diags
+}
+
+func (t *applyModuleResource) waitForJobCompletion(ctx context.Context, jobID *string, input *createRunInput) error {
+	if jobID == nil {
+		return fmt.Errorf("nil job ID")
+	}
+
+	pollInterval := input.pollInterval
+	if pollInterval <= 0 {
+		pollInterval = jobCompletionPollInterval
+	}
+
+	// How much of the logs has already been streamed.
+	var logOffset int32
+
+	// Poll until job has finished or the context expires.
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context expired while waiting for job ID %s", *jobID)
+		case <-time.After(pollInterval):
+		}
+
+		var job *sdktypes.Job
+		err := withRetry(ctx, input.retry, "GetJob", func() (err error) {
+			job, err = t.client.Job.GetJob(ctx, &sdktypes.GetJobInput{
+				ID: *jobID,
+			})
+			return err
+		})
+		if err != nil {
+			if tharsis.IsNotFoundError(err) {
+				return fmt.Errorf("job ID %s no longer exists, so its run may have been deleted: %w", *jobID, err)
+			}
+			return fmt.Errorf("failed to get job ID %s: %v", *jobID, err)
+		}
+
+		if input.streamLogs {
+			t.streamJobLogs(ctx, input.retry, job, *jobID, &logOffset)
+		}
+
+		// The caller finds out from the run's plan or apply whether the job succeeded.
+		if jobTerminalStatuses[job.Status] {
+			return nil
+		}
+
+		// A job that hasn't started is never run if its run has ended, such as when it was canceled.
+		if job.CancelRequested || (job.Status == "queued") || (job.Status == "pending") {
+			if err = t.checkRunNotEnded(ctx, input, job); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkRunNotEnded returns an error if the run of a job that hasn't finished has ended,
+// so the job will never finish.
+func (t *applyModuleResource) checkRunNotEnded(ctx context.Context, input *createRunInput, job *sdktypes.Job) error {
+	var run *sdktypes.Run
+	err := withRetry(ctx, input.retry, "GetRun", func() (err error) {
+		run, err = t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: job.RunID})
+		return err
+	})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			return fmt.Errorf("run ID %s of job ID %s no longer exists: %w", job.RunID, job.Metadata.ID, err)
+		}
+		return fmt.Errorf("failed to get run ID %s of job ID %s: %v", job.RunID, job.Metadata.ID, err)
+	}
+
+	switch run.Status {
+	case sdktypes.RunApplied, sdktypes.RunCanceled, sdktypes.RunErrored, sdktypes.RunPlannedAndFinished:
+		return fmt.Errorf("run ID %s ended with status %s before its %s job ID %s finished",
+			run.Metadata.ID, run.Status, job.Type, job.Metadata.ID)
+	}
+
+	return nil
+}
+
+// waitForApproval waits for a planned run to be approved, which starts its apply.
+// If the run is canceled or the context expires first, an error naming the run is returned.
+func (t *applyModuleResource) waitForApproval(ctx context.Context, input *createRunInput,
+	plannedRun *sdktypes.Run,
+) (*sdktypes.Run, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	runID := plannedRun.Metadata.ID
+	wsPath := t.paths.resolve(input.model.WorkspacePath.ValueString())
+	approvalMessage := fmt.Sprintf("Run %s in workspace %s is awaiting approval", runID, wsPath)
+	tflog.Warn(ctx, approvalMessage, map[string]any{"run_id": runID, "workspace_path": wsPath})
+
+	pollInterval := input.pollInterval
+	if pollInterval <= 0 {
+		pollInterval = jobCompletionPollInterval
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			diags.AddError(approvalMessage,
+				"The run was not approved before the timeout. Approve it in Tharsis and apply again, or set auto_approve to true.",
+			)
+			return nil, diags
+		case <-time.After(pollInterval):
+			var run *sdktypes.Run
+			err := withRetry(ctx, input.retry, "GetRun", func() (err error) {
+				run, err = t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: runID})
+				return err
+			})
+			if err != nil {
+				diags.Append(apiErrorDiagnostic("Failed to get run awaiting approval", err, input.model.WorkspacePath.ValueString()))
+				return nil, diags
+			}
+
+			switch run.Status {
+			case sdktypes.RunPlanned:
+				continue
+			case sdktypes.RunCanceled:
+				diags.AddError(fmt.Sprintf("Run %s in workspace %s was canceled instead of approved", runID, wsPath), "")
+				return nil, diags
+			default:
+				return run, diags
+			}
+		}
+	}
+}
+
+// verifyModuleDigest checks that the module resolved by a planned run has the expected checksum.
+// Tharsis only records the digest of modules from its module registry.
+func verifyModuleDigest(run *sdktypes.Run, expected string) error {
+	if run.ModuleDigest == nil || *run.ModuleDigest == "" {
+		return fmt.Errorf("run %s has no module digest to verify; only modules from the Tharsis module registry have one",
+			run.Metadata.ID)
+	}
+
+	if normalizeDigest(*run.ModuleDigest) != normalizeDigest(expected) {
+		moduleSource := ptr.ToString(run.ModuleSource)
+		if run.ModuleVersion != nil {
+			moduleSource += " version " + *run.ModuleVersion
+		}
+		return fmt.Errorf("module %s has checksum sha256:%s, expected %s",
+			moduleSource, normalizeDigest(*run.ModuleDigest), expected)
+	}
+
+	return nil
+}
+
+// cancelRun cancels a run whose context was cancelled and briefly waits for the cancellation to be acknowledged.
+// Otherwise, the run would keep going and conflict with the next attempt.
+func (t *applyModuleResource) cancelRun(runID string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// The original context is already done, so a new one is required.
+	ctx, cancel := context.WithTimeout(context.Background(), runCancellationTimeout)
+	defer cancel()
+
+	if _, err := t.client.Run.CancelRun(ctx, &sdktypes.CancelRunInput{
+		RunID: runID,
+	}); err != nil {
+		diags.AddError(fmt.Sprintf("Failed to cancel run %s after Terraform was interrupted", runID), err.Error())
+		return diags
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			diags.AddWarning(
+				fmt.Sprintf("Requested cancellation of run %s after Terraform was interrupted", runID),
+				"The cancellation was not acknowledged in time; check the run's status before trying again.",
+			)
+			return diags
+		case <-time.After(runCancellationPollInterval):
+			run, err := t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: runID})
+			if err != nil {
+				continue
+			}
+
+			if run.Status == sdktypes.RunCanceled {
+				diags.AddWarning(fmt.Sprintf("Canceled run %s after Terraform was interrupted", runID), "")
+				return diags
+			}
+		}
+	}
+}
+
+// uploadConfigurationVersion uploads the directory as a configuration version of the target workspace
+// and waits for the upload to finish, returning the configuration version's ID.
+func (t *applyModuleResource) uploadConfigurationVersion(ctx context.Context, input *createRunInput) (string, error) {
+	wsPath := t.paths.resolve(input.model.WorkspacePath.ValueString())
+
+	var configurationVersion *sdktypes.ConfigurationVersion
+	err := withRetry(ctx, input.retry, "CreateConfigurationVersion", func() (err error) {
+		configurationVersion, err = t.client.ConfigurationVersion.CreateConfigurationVersion(ctx,
+			&sdktypes.CreateConfigurationVersionInput{
+				WorkspacePath: wsPath,
+				Speculative:   ptr.Bool((input.model.Speculative.ValueBool() || input.detectingDrift) && !input.doDestroy),
+			})
+		return err
 	})
-	resp.Diagnostics.Append(newDiags2...)
-	if resp.Diagnostics.HasError() {
-		return
+	if err != nil {
+		return "", err
 	}
 
-	// Transform the resolved variables from the destroy run.
-	resolvedVars, diags := t.toProviderOutputVariables(ctx, didRun.resolvedVariables)
-	if diags.HasError() {
-		resp.Diagnostics.Append(diags...)
-		return
+	if err = withRetry(ctx, input.retry, "UploadConfigurationVersion", func() error {
+		return t.client.ConfigurationVersion.UploadConfigurationVersion(ctx,
+			&sdktypes.UploadConfigurationVersionInput{
+				WorkspacePath:          wsPath,
+				ConfigurationVersionID: configurationVersion.Metadata.ID,
+				DirectoryPath:          input.model.Directory.ValueString(),
+			})
+	}); err != nil {
+		return "", err
 	}
-	state.ResolvedVariables = resolvedVars
 
-	// Set the response state to be fully-populated, with or without error.
-	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	// Poll until the upload has been processed or the context expires.
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("context expired while waiting for configuration version %s to upload",
+				configurationVersion.Metadata.ID)
+		case <-time.After(configurationVersionPollInterval):
+			var updated *sdktypes.ConfigurationVersion
+			err := withRetry(ctx, input.retry, "GetConfigurationVersion", func() (err error) {
+				updated, err = t.client.ConfigurationVersion.GetConfigurationVersion(ctx,
+					&sdktypes.GetConfigurationVersionInput{
+						ID: configurationVersion.Metadata.ID,
+					})
+				return err
+			})
+			if err != nil {
+				return "", err
+			}
+
+			switch updated.Status {
+			case "pending":
+				continue
+			case "uploaded":
+				return updated.Metadata.ID, nil
+			default:
+				return "", fmt.Errorf("configuration version %s upload failed with status %s",
+					updated.Metadata.ID, updated.Status)
+			}
+		}
+	}
 }
 
-// createRun launches a remote run and waits for it to complete.
-func (t *applyModuleResource) createRun(ctx context.Context, input *createRunInput) (*createRunOutput, diag.Diagnostics) {
+// withTimeout returns a context bounded by the configured timeout for the operation, if any.
+// The returned cancel function must always be called.
+func (t *applyModuleResource) withTimeout(ctx context.Context, model *ApplyModuleModel,
+	operation string,
+) (context.Context, context.CancelFunc, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
-	// Convert the input variables.
-	vars, err := t.copyRunVariablesToInput(ctx, &input.model.Variables)
-	if err != nil {
-		diags.AddError("Failed to convert variables to SDK types", err.Error())
-		return nil, diags
+	if model.Timeouts.IsNull() || model.Timeouts.IsUnknown() {
+		return ctx, func() {}, diags
 	}
 
-	// Call CreateRun
-	var moduleVersion *string
-	if !input.model.ModuleVersion.IsUnknown() {
-		moduleVersion = ptr.String(input.model.ModuleVersion.ValueString())
-	}
-	createdRun, err := t.client.Run.CreateRun(ctx, &sdktypes.CreateRunInput{
-		WorkspacePath: input.model.WorkspacePath.ValueString(),
-		IsDestroy:     input.doDestroy,
-		ModuleSource:  ptr.String(input.model.ModuleSource.ValueString()),
-		ModuleVersion: moduleVersion,
-		Refresh:       input.model.Refresh.ValueBool(),
-		Variables:     vars,
-	})
-	if err != nil {
-		diags.AddError("Failed to create run", err.Error())
-		return nil, diags
+	var timeouts ApplyModuleTimeoutsModel
+	diags.Append(model.Timeouts.As(ctx, &timeouts, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return ctx, func() {}, diags
 	}
 
-	if err = t.waitForJobCompletion(ctx, createdRun.Plan.CurrentJobID); err != nil {
-		diags.AddError("Failed to wait for plan job completion", err.Error())
-		return nil, diags
+	var value types.String
+	switch operation {
+	case "create":
+		value = timeouts.Create
+	case "update":
+		value = timeouts.Update
+	case "delete":
+		value = timeouts.Delete
+	}
+
+	if value.IsNull() || value.IsUnknown() {
+		return ctx, func() {}, diags
 	}
 
-	plannedRun, err := t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: createdRun.Metadata.ID})
+	// The value was already checked by ValidateConfig.
+	timeout, err := time.ParseDuration(value.ValueString())
 	if err != nil {
-		diags.AddError("Failed to get planned run", err.Error())
-		return nil, diags
+		diags.AddError(fmt.Sprintf("Failed to parse %s timeout", operation), err.Error())
+		return ctx, func() {}, diags
 	}
 
-	// If the plan fails, both plannedRun.Status and plannedRun.Plan.Status are "errored".
-	// If the plan succeeds, plannedRun.Status is "planned",
-	// while plannedRun.Plan.Status is "finished".
-	//
-	switch plannedRun.Plan.Status {
-	case sdktypes.PlanCanceled:
-		diags.AddError("Plan was canceled", string(plannedRun.Plan.Status))
-		return nil, diags
-	case sdktypes.PlanErrored:
-		// Bring in any error message(s) from the finished inner plan run.
-		innerPlanRunDiags := t.extractRunError(ctx, plannedRun)
-		if innerPlanRunDiags.HasError() {
-			diags.Append(innerPlanRunDiags...)
-		} else {
-			diags.AddError("Plan failed with unknown error", string(plannedRun.Plan.Status))
-		}
-		return nil, diags
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, diags
+}
+
+// getPollInterval returns the configured job poll interval or the default.
+func (t *applyModuleResource) getPollInterval(model *ApplyModuleModel) time.Duration {
+	if model.JobPollInterval.IsNull() || model.JobPollInterval.IsUnknown() {
+		return jobCompletionPollInterval
 	}
 
-	// Capture the run ID.
-	runID := plannedRun.Metadata.ID
+	// The value was already checked by ValidateConfig.
+	interval, err := time.ParseDuration(model.JobPollInterval.ValueString())
+	if err != nil || interval <= 0 {
+		return jobCompletionPollInterval
+	}
 
-	// Get the resolved variables from the run.
-	resolvedPlanVars, err := t.client.Run.GetRunVariables(ctx, &sdktypes.GetRunInput{ID: runID})
-	if err != nil {
-		diags.AddError("Failed to get resolved variables", err.Error())
-		return nil, diags
+	return interval
+}
+
+// getRetryPolicy returns the configured retry policy, filling in defaults for anything not set.
+func (t *applyModuleResource) getRetryPolicy(ctx context.Context, model *ApplyModuleModel) (retryPolicy, diag.Diagnostics) {
+	policy := defaultRetryPolicy()
+
+	if model.Retry.IsNull() || model.Retry.IsUnknown() {
+		return policy, nil
 	}
 
-	if plannedRun.Status == sdktypes.RunPlannedAndFinished {
-		result := &createRunOutput{
-			resolvedVariables: resolvedPlanVars,
-		}
+	var retry ApplyModuleRetryModel
+	diags := model.Retry.As(ctx, &retry, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return policy, diags
+	}
 
-		if plannedRun.ModuleVersion != nil {
-			result.moduleVersion = *plannedRun.ModuleVersion
+	// The values were already checked by ValidateConfig.
+	if !retry.MaxAttempts.IsNull() && !retry.MaxAttempts.IsUnknown() {
+		policy.maxAttempts = int(retry.MaxAttempts.ValueInt64())
+	}
+	if backoff, err := time.ParseDuration(retry.InitialBackoff.ValueString()); err == nil && backoff > 0 {
+		policy.initialBackoff = backoff
+	}
+	if backoff, err := time.ParseDuration(retry.MaxBackoff.ValueString()); err == nil && backoff > 0 {
+		policy.maxBackoff = backoff
+	}
+	if !retry.RetryOn.IsNull() && !retry.RetryOn.IsUnknown() {
+		var classes []string
+		diags.Append(retry.RetryOn.ElementsAs(ctx, &classes, false)...)
+
+		policy.classes = map[string]bool{}
+		for _, class := range classes {
+			policy.classes[class] = true
 		}
-		return result, diags
 	}
 
-	// Do the apply run.
-	appliedRun, err := t.client.Run.ApplyRun(ctx, &sdktypes.ApplyRunInput{
-		RunID:   runID,
-		Comment: &applyRunComment,
-	})
-	if err != nil {
-		diags.AddError("Failed to apply a run", err.Error())
-		return nil, diags
+	return policy, diags
+}
+
+// refreshWorkspaces returns the service to read workspaces with while refreshing,
+// which shares them with other resources.
+func (t *applyModuleResource) refreshWorkspaces() workspaceService {
+	if t.workspaces == nil {
+		return t.client.Workspaces
 	}
 
-	// Make sure the run has an apply.
-	if appliedRun.Apply == nil {
-		msg := fmt.Sprintf("Created run does not have an apply: %s", appliedRun.Metadata.ID)
-		diags.AddError(msg, "")
-		return nil, diags
+	return t.workspaces
+}
+
+// getCurrentStateVersion sets the state version ID and outputs from the target workspace's current state version.
+func (t *applyModuleResource) getCurrentStateVersion(ctx context.Context, model *ApplyModuleModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// A speculative plan doesn't produce a new state version.
+	if model.Speculative.ValueBool() {
+		return t.setStateVersion(ctx, model, nil)
 	}
 
-	if err = t.waitForJobCompletion(ctx, appliedRun.Apply.CurrentJobID); err != nil {
-		diags.AddError("Failed to wait for apply job completion", err.Error())
-		return nil, diags
+	retry, retryDiags := t.getRetryPolicy(ctx, model)
+	diags.Append(retryDiags...)
+	if diags.HasError() {
+		return diags
 	}
 
-	finishedRun, err := t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{ID: appliedRun.Metadata.ID})
+	wsPath := t.paths.resolve(model.WorkspacePath.ValueString())
+	var ws *sdktypes.Workspace
+	err := withRetry(ctx, retry, "GetWorkspace", func() (err error) {
+		ws, err = t.client.Workspaces.GetWorkspace(ctx, &sdktypes.GetWorkspaceInput{
+			Path: &wsPath,
+		})
+		return err
+	})
 	if err != nil {
-		diags.AddError("Failed to get finished run", err.Error())
-		return nil, diags
+		diags.Append(apiErrorDiagnostic(fmt.Sprintf("Failed to get specified workspace by path: %s", wsPath), err, wsPath))
+		return diags
 	}
 
-	// If an apply job succeeds, finishedRun.Status is "applied" and
-	// finishedRun.Apply.Status is "finished".
-	switch finishedRun.Apply.Status {
-	case sdktypes.ApplyCanceled:
-		diags.AddError("Apply was canceled", string(finishedRun.Apply.Status))
-		return nil, diags
-	case sdktypes.ApplyErrored:
-		// Bring in any error message(s) from the finished inner apply run.
-		innerApplyRunDiags := t.extractRunError(ctx, finishedRun)
-		if innerApplyRunDiags.HasError() {
-			diags.Append(innerApplyRunDiags...)
-		} else {
-			diags.AddError("Apply failed with unknown error", string(finishedRun.Apply.Status))
-		}
-		return nil, diags
-	}
+	return t.setStateVersion(ctx, model, ws.CurrentStateVersion)
+}
 
-	// In case of a rainy day, make sure the ModuleSource and ModuleVersion *string aren't nil.
-	if finishedRun.ModuleSource == nil {
-		diags.AddError("Finished run's module source is nil.", "")
-		return nil, diags
+// setStateVersion copies the ID and outputs of a state version into the model, setting them null if there is no state version.
+func (t *applyModuleResource) setStateVersion(ctx context.Context, model *ApplyModuleModel,
+	stateVersion *sdktypes.StateVersion,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if stateVersion == nil {
+		model.StateVersionID = types.StringNull()
+		model.Outputs = types.MapNull(types.StringType)
+		model.OutputsJSON = types.MapNull(types.StringType)
+		return diags
 	}
-	if finishedRun.ModuleVersion == nil {
-		diags.AddError("Finished run's module version is nil.", "")
-		return nil, diags
+
+	model.StateVersionID = types.StringValue(stateVersion.Metadata.ID)
+
+	outputs, err := stateVersionOutputs(stateVersion, false)
+	if err != nil {
+		diags.AddError("Failed to parse workspace outputs", err.Error())
+		return diags
 	}
 
-	// Get the resolved variables from the run.
-	resolvedApplyVars, err := t.client.Run.GetRunVariables(ctx, &sdktypes.GetRunInput{ID: finishedRun.Metadata.ID})
+	outputsJSON, err := stateVersionOutputs(stateVersion, true)
 	if err != nil {
-		diags.AddError("Failed to get resolved variables", err.Error())
-		return nil, diags
+		diags.AddError("Failed to parse workspace outputs", err.Error())
+		return diags
 	}
 
-	// The module version was checked above, so it's safe to dereference.
-	// These diags may include those from the inner run if it errored out.
-	return &createRunOutput{
-		resolvedVariables: resolvedApplyVars,
-		moduleVersion:     *finishedRun.ModuleVersion,
-	}, diags
+	var newDiags diag.Diagnostics
+	model.Outputs, newDiags = types.MapValueFrom(ctx, types.StringType, outputs)
+	diags.Append(newDiags...)
+
+	model.OutputsJSON, newDiags = types.MapValueFrom(ctx, types.StringType, outputsJSON)
+	diags.Append(newDiags...)
+
+	return diags
 }
 
-func (t *applyModuleResource) waitForJobCompletion(ctx context.Context, jobID *string) error {
-	if jobID == nil {
-		return fmt.Errorf("nil job ID")
+// copyRunSummary copies the run ID and plan summary of a finished run into the model.
+func (t *applyModuleResource) copyRunSummary(didRun *createRunOutput, model *ApplyModuleModel) {
+	model.RunID = types.StringValue(didRun.runID)
+	model.Status = types.StringValue(didRun.status)
+
+	// A directory has neither a module version nor a module source.
+	if model.Directory.IsNull() {
+		model.ModuleVersion = types.StringValue(didRun.moduleVersion)
+		model.DirectoryHash = types.StringNull()
+		model.ConfigurationVersionID = types.StringNull()
+	} else {
+		model.ModuleVersion = types.StringNull()
+		model.ConfigurationVersionID = types.StringValue(didRun.configurationVersionID)
 	}
 
-	// Poll until job has finished or the context expires.
-	for {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("context expired while waiting for job ID %s", *jobID)
-		case <-time.After(jobCompletionPollInterval):
-			job, err := t.client.Job.GetJob(ctx, &sdktypes.GetJobInput{
-				ID: *jobID,
+	model.ResourceAdditions = types.Int64Value(didRun.resourceAdditions)
+	model.ResourceChanges = types.Int64Value(didRun.resourceChanges)
+	model.ResourceDestructions = types.Int64Value(didRun.resourceDestructions)
+	model.ChangesDetected = types.BoolValue(didRun.changesDetected)
+}
+
+// streamJobLogs emits the logs of a job past the offset to the provider's log, then advances the offset.
+// Failing to get the logs is not fatal, since the job itself may still succeed.
+func (t *applyModuleResource) streamJobLogs(ctx context.Context, retry retryPolicy,
+	job *sdktypes.Job, jobID string, offset *int32,
+) {
+	for *offset < int32(job.LogSize) {
+		limit := int32(job.LogSize) - *offset
+		if limit > logChunkSize {
+			limit = logChunkSize
+		}
+
+		var newLogs string
+		err := withRetry(ctx, retry, "GetJobLogs", func() error {
+			logs, err := t.client.Job.GetJobLogs(ctx, &sdktypes.GetJobLogsInput{
+				JobID: jobID,
+				Start: *offset,
+				Limit: &limit,
 			})
 			if err != nil {
-				return fmt.Errorf("failed to get job ID %s", *jobID)
+				return err
 			}
+			newLogs = logs.Logs
+			return nil
+		})
+		if err != nil {
+			tflog.Warn(ctx, "Failed to get job logs", map[string]any{"job_id": jobID, "error": err.Error()})
+			return
+		}
 
-			if job.Status == "finished" {
-				return nil
-			}
+		// Workaround: The API returns one more character than asked for.
+		if len(newLogs) > int(limit) {
+			newLogs = newLogs[:limit]
+		}
+
+		if newLogs == "" {
+			return
+		}
+		*offset += int32(len(newLogs))
+
+		for _, line := range strings.Split(strings.TrimSuffix(newLogs, "\n"), "\n") {
+			tflog.Info(ctx, line, map[string]any{"job_id": jobID, "job_type": string(job.Type)})
 		}
 	}
 }
 
-// getCurrentApplied returns an ApplyModuleModel reflecting what is currently applied.
-func (t *applyModuleResource) getCurrentApplied(ctx context.Context,
+// getCurrentApplied returns an ApplyModuleModel reflecting what is currently applied,
+// reading the workspace with the given service.
+func (t *applyModuleResource) getCurrentApplied(ctx context.Context, workspaces workspaceService,
 	tfState ApplyModuleModel,
 ) (*appliedModuleInfo, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
 	// Get latest run on the target workspace.
-	wsPath := tfState.WorkspacePath.ValueString()
-	ws, err := t.client.Workspaces.GetWorkspace(ctx, &sdktypes.GetWorkspaceInput{
+	wsPath := t.paths.resolve(tfState.WorkspacePath.ValueString())
+	ws, err := workspaces.GetWorkspace(ctx, &sdktypes.GetWorkspaceInput{
 		Path: &wsPath,
 	})
 	if err != nil {
-		diags.AddError(fmt.Sprintf("Failed to get specified workspace by path: %s", wsPath), err.Error())
+		diags.Append(apiErrorDiagnostic(fmt.Sprintf("Failed to get specified workspace by path: %s", wsPath), err, wsPath))
 		return nil, diags
 	}
 
 	// Get whatever information may be available about the latest applied module.
 	if ws.CurrentStateVersion != nil {
-		moduleInfoOutput := &appliedModuleInfo{}
+		moduleInfoOutput := &appliedModuleInfo{
+			stateVersion: ws.CurrentStateVersion,
+		}
 
 		if ws.CurrentStateVersion.RunID != "" {
 			latestRun, err := t.client.Run.GetRun(ctx, &sdktypes.GetRunInput{
 				ID: ws.CurrentStateVersion.RunID,
 			})
 			if err != nil {
-				diags.AddError("Failed to get latest run", err.Error())
+				diags.Append(apiErrorDiagnostic("Failed to get latest run", err, wsPath))
 				return nil, diags
 			}
 
@@ -647,7 +2164,7 @@ func (t *applyModuleResource) getCurrentApplied(ctx context.Context,
 }
 
 // extractRunError extracts the error from a run's logs (if the run errored out).
-func (t *applyModuleResource) extractRunError(ctx context.Context, run *sdktypes.Run) diag.Diagnostics {
+func (t *applyModuleResource) extractRunError(ctx context.Context, policy retryPolicy, run *sdktypes.Run) diag.Diagnostics {
 	var diags diag.Diagnostics
 	var jobID string
 
@@ -676,8 +2193,12 @@ func (t *applyModuleResource) extractRunError(ctx context.Context, run *sdktypes
 	}
 
 	// Must get the job to know the size of the logs to paginate in reverse.
-	job, err := t.client.Job.GetJob(ctx, &sdktypes.GetJobInput{
-		ID: jobID,
+	var job *sdktypes.Job
+	err := withRetry(ctx, policy, "GetJob", func() (err error) {
+		job, err = t.client.Job.GetJob(ctx, &sdktypes.GetJobInput{
+			ID: jobID,
+		})
+		return err
 	})
 	if err != nil {
 		diags.AddError("Failed to get job", err.Error())
@@ -694,10 +2215,14 @@ func (t *applyModuleResource) extractRunError(ctx context.Context, run *sdktypes
 		nextChunkSize = int32(job.LogSize)
 	}
 	for {
-		logs, err := t.client.Job.GetJobLogs(ctx, &sdktypes.GetJobLogsInput{
-			JobID: jobID,
-			Start: currentStart,
-			Limit: &nextChunkSize,
+		var logs *sdktypes.JobLogs
+		err = withRetry(ctx, policy, "GetJobLogs", func() (err error) {
+			logs, err = t.client.Job.GetJobLogs(ctx, &sdktypes.GetJobLogsInput{
+				JobID: jobID,
+				Start: currentStart,
+				Limit: &nextChunkSize,
+			})
+			return err
 		})
 		if err != nil {
 			diags.AddError("Failed to get job logs", err.Error())
@@ -730,6 +2255,30 @@ func (t *applyModuleResource) extractRunError(ctx context.Context, run *sdktypes
 		}
 	}
 
+	// A prefix so the user knows what module source and workspace the error came from.
+	prefix := fmt.Sprintf("Failed to %s module %s in workspace %s",
+		strings.ToLower(string(job.Type)), ptr.ToString(run.ModuleSource), run.WorkspacePath,
+	)
+
+	// Machine-readable logs have no plain-text error, so the whole log will have been read.
+	// Report each of their diagnostics separately.
+	if logDiags := parseJSONLogDiagnostics(allLogs); len(logDiags) > 0 {
+		for _, d := range logDiags {
+			detail := d.Detail
+			if d.Address != "" {
+				detail = strings.TrimSpace(fmt.Sprintf("Address: %s\n\n%s", d.Address, d.Detail))
+			}
+
+			switch d.Severity {
+			case "error":
+				diags.AddError(prefix+": "+d.Summary, detail)
+			case "warning":
+				diags.AddWarning(prefix+": "+d.Summary, detail)
+			}
+		}
+		return diags
+	}
+
 	// Find the beginning of the error message to return.
 	startIx := strings.Index(allLogs, lookForError)
 	if startIx < 0 {
@@ -745,16 +2294,15 @@ func (t *applyModuleResource) extractRunError(ctx context.Context, run *sdktypes
 	}
 
 	// Add a prefix line so the user knows what module source and workspace the error came from.
-	diags.AddError(fmt.Sprintf(
-		"Failed to %s module %s in workspace %s\n",
-		strings.ToLower(string(job.Type)), ptr.ToString(run.ModuleSource), run.WorkspacePath,
-	)+strings.TrimPrefix(foundMessage, "Error: "), "")
+	diags.AddError(prefix+"\n"+strings.TrimPrefix(foundMessage, "Error: "), "")
 
 	return diags
 }
 
-// copyRunVariablesToInput converts from RunVariableModel to SDK equivalent.
-func (t *applyModuleResource) copyRunVariablesToInput(ctx context.Context, list *basetypes.ListValue,
+// copyRunVariablesToInput converts from RunVariableModel to SDK equivalent,
+// followed by the environment variables from the map, in order of their keys.
+func (t *applyModuleResource) copyRunVariablesToInput(ctx context.Context, list *basetypes.SetValue,
+	environmentVariables types.Map,
 ) ([]sdktypes.RunVariable, error) {
 	result := []sdktypes.RunVariable{}
 
@@ -769,6 +2317,16 @@ func (t *applyModuleResource) copyRunVariablesToInput(ctx context.Context, list
 			return nil, err
 		}
 
+		// A variable that refers to a namespace's variable is resolved by Tharsis.
+		if model.NamespacePath != "" {
+			result = append(result, sdktypes.RunVariable{
+				NamespacePath: ptr.String(t.paths.resolve(model.NamespacePath)),
+				Key:           model.Key,
+				Category:      sdktypes.VariableCategory(model.Category),
+			})
+			continue
+		}
+
 		result = append(result, sdktypes.RunVariable{
 			Value:    &model.Value,
 			Key:      model.Key,
@@ -776,6 +2334,23 @@ func (t *applyModuleResource) copyRunVariablesToInput(ctx context.Context, list
 		})
 	}
 
+	environment := map[string]string{}
+	if diags := environmentVariables.ElementsAs(ctx, &environment, false); diags.HasError() {
+		return nil, fmt.Errorf("failed to convert environment variables: %v", diags)
+	}
+	keys := make([]string, 0, len(environment))
+	for key := range environment {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		result = append(result, sdktypes.RunVariable{
+			Value:    ptr.String(environment[key]),
+			Key:      key,
+			Category: sdktypes.EnvironmentVariableCategory,
+		})
+	}
+
 	// Terraform generally wants to see nil rather than an empty list.
 	if len(result) == 0 {
 		result = nil
@@ -784,14 +2359,66 @@ func (t *applyModuleResource) copyRunVariablesToInput(ctx context.Context, list
 	return result, nil
 }
 
+// encodeAppliedVariables encodes the variables that were supplied to a run, as opposed to
+// those inherited from a namespace, so they can be kept in private state.
+func (t *applyModuleResource) encodeAppliedVariables(arg []sdktypes.RunVariable) ([]byte, error) {
+	result := []appliedVariable{}
+
+	for _, variable := range arg {
+		if variable.NamespacePath != nil {
+			continue
+		}
+
+		result = append(result, appliedVariable{
+			Value:    variable.Value,
+			Key:      variable.Key,
+			Category: string(variable.Category),
+		})
+	}
+
+	return json.Marshal(result)
+}
+
+// decodeAppliedVariables decodes the variables kept in private state to SDK types.
+func (t *applyModuleResource) decodeAppliedVariables(arg []byte) ([]sdktypes.RunVariable, error) {
+	var applied []appliedVariable
+	if err := json.Unmarshal(arg, &applied); err != nil {
+		return nil, err
+	}
+
+	result := []sdktypes.RunVariable{}
+	for _, variable := range applied {
+		result = append(result, sdktypes.RunVariable{
+			Value:    variable.Value,
+			Key:      variable.Key,
+			Category: sdktypes.VariableCategory(variable.Category),
+		})
+	}
+
+	return result, nil
+}
+
 // toProviderOutputVariables converts SDK variables from a finished run to the types the provider can return to Terraform.
+// Any variable marked sensitive in the input variables is omitted, so its value isn't exposed.
 func (t *applyModuleResource) toProviderOutputVariables(
 	ctx context.Context,
 	arg []sdktypes.RunVariable,
+	inputVariables *basetypes.SetValue,
 ) (basetypes.ListValue, diag.Diagnostics) {
 	variables := []types.Object{}
 
+	sensitive, err := t.getSensitiveVariables(ctx, inputVariables)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Failed to convert variables to SDK types", err.Error())
+		return basetypes.ListValue{}, diags
+	}
+
 	for _, variable := range arg {
+		if sensitive[string(variable.Category)+"/"+variable.Key] {
+			continue
+		}
+
 		val := ""
 		if variable.Value != nil {
 			val = *variable.Value
@@ -825,6 +2452,30 @@ func (t *applyModuleResource) toProviderOutputVariables(
 	return list, nil
 }
 
+// getSensitiveVariables returns the category and key of each input variable marked sensitive.
+func (t *applyModuleResource) getSensitiveVariables(ctx context.Context, list *basetypes.SetValue,
+) (map[string]bool, error) {
+	result := map[string]bool{}
+
+	for _, element := range list.Elements() {
+		terraformValue, err := element.ToTerraformValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var model RunVariableModel
+		if err = terraformValue.As(&model); err != nil {
+			return nil, err
+		}
+
+		if model.Sensitive {
+			result[model.Category+"/"+model.Key] = true
+		}
+	}
+
+	return result, nil
+}
+
 func (t *applyModuleResource) outputVariableAttributes() map[string]attr.Type {
 	return map[string]attr.Type{
 		"value":          types.StringType,
@@ -833,3 +2484,51 @@ func (t *applyModuleResource) outputVariableAttributes() map[string]attr.Type {
 		"category":       types.StringType,
 	}
 }
+
+// hashDirectory returns a hash of the names and contents of the files in a directory, so
+// changes to them can be detected.  Like the upload, it skips .git and .terraform directories.
+func hashDirectory(dir string) (string, error) {
+	hash := sha256.New()
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != dir && (entry.Name() == ".git" || entry.Name() == ".terraform") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		hash.Write([]byte(filepath.ToSlash(rel) + "\x00"))
+
+		file, err := os.Open(path) // nolint
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if _, err = io.Copy(hash, file); err != nil {
+			return err
+		}
+		hash.Write([]byte{0})
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// normalizeDigest strips the optional algorithm prefix from a SHA-256 digest and lowercases it for comparison.
+func normalizeDigest(digest string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(digest), "sha256:"))
+}
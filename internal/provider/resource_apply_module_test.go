@@ -55,9 +55,14 @@ func TestApplyModule(t *testing.T) {
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "workspace_path", ws1Path),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "module_source", moduleSource),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "refresh", strconv.FormatBool(true)),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.value", varValueBase+"1"),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.key", varKey),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.category", varCategory),
+					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "job_poll_interval", "2s"),
+					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "timeouts.create", "30m"),
+					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("tharsis_apply_module.tam", "variables.*", map[string]string{
+						"value":    varValueBase + "1",
+						"key":      varKey,
+						"category": varCategory,
+					}),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.value", varValueBase+"1"),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.namespace_path", ""),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.key", varKey),
@@ -74,9 +79,12 @@ func TestApplyModule(t *testing.T) {
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "workspace_path", ws1Path),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "module_source", moduleSource),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "refresh", strconv.FormatBool(true)),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.value", varValueBase+"1"),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.key", varKey),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.category", varCategory),
+					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("tharsis_apply_module.tam", "variables.*", map[string]string{
+						"value":    varValueBase + "1",
+						"key":      varKey,
+						"category": varCategory,
+					}),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.value", varValueBase+"1"),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.namespace_path", ""),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.key", varKey),
@@ -93,9 +101,12 @@ func TestApplyModule(t *testing.T) {
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "workspace_path", ws1Path),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "module_source", moduleSource),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "refresh", strconv.FormatBool(true)),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.value", varValueBase+"2"),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.key", varKey),
-					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.0.category", varCategory),
+					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "variables.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("tharsis_apply_module.tam", "variables.*", map[string]string{
+						"value":    varValueBase + "2",
+						"key":      varKey,
+						"category": varCategory,
+					}),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.value", varValueBase+"2"),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.namespace_path", ""),
 					resource.TestCheckResourceAttr("tharsis_apply_module.tam", "resolved_variables.0.key", varKey),
@@ -103,6 +114,17 @@ func TestApplyModule(t *testing.T) {
 				),
 			},
 
+			// Import the module currently applied to the workspace.
+			{
+				Config:            testApplyModuleConfigurationCreate() + testDoApplyCreateRun(2),
+				ResourceName:      "tharsis_apply_module.tam",
+				ImportStateId:     ws1Path,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The ID is generated, and these only affect future runs.
+				ImportStateVerifyIgnore: []string{"id", "job_poll_interval", "timeouts"},
+			},
+
 			// Do a destroy/delete run.
 			{
 				Config: testApplyModuleConfigurationCreate(), // Remove the tharsis_apply_module resource.
@@ -182,6 +204,12 @@ func testDoApplyCreateRun(val int) string {
 resource "tharsis_apply_module" "tam" {
   workspace_path = "%s"
   module_source  = "%s"
+  job_poll_interval = "2s"
+  timeouts = {
+    create = "30m"
+    update = "30m"
+    delete = "30m"
+  }
   variables      = [
     {
       value = "%s%d"
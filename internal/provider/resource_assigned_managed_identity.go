@@ -86,7 +86,7 @@ func (t *assignedManagedIdentityResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	t.client = req.ProviderData.(*tharsisProvider).client
 }
 
 func (t *assignedManagedIdentityResource) Create(ctx context.Context,
@@ -106,10 +106,7 @@ func (t *assignedManagedIdentityResource) Create(ctx context.Context,
 		},
 	)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error getting workspace",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error getting workspace", err, assignment.WorkspaceID.ValueString()))
 		return
 	}
 
@@ -121,10 +118,7 @@ func (t *assignedManagedIdentityResource) Create(ctx context.Context,
 			WorkspacePath:     workspace.FullPath,
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating assigned managed identity",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating assigned managed identity", err, assignment.WorkspaceID.ValueString()))
 		return
 	}
 
@@ -154,18 +148,11 @@ func (t *assignedManagedIdentityResource) Read(ctx context.Context,
 		})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
-			resp.Diagnostics.AddError(
-				"Error finding assigned specified workspace and its managed identities",
-				"either the workspace no longer exists or there was another problem with database access",
-			)
+			removeMissingResource(ctx, resp, "assigned managed identity", state.ManagedIdentityID.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading assigned managed identities",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading assigned managed identities", err, state.WorkspaceID.ValueString()))
 		return
 	}
 
@@ -182,7 +169,7 @@ func (t *assignedManagedIdentityResource) Read(ctx context.Context,
 		}
 	}
 	if found == nil {
-		resp.State.RemoveResource(ctx)
+		removeMissingResource(ctx, resp, "assigned managed identity", wantID)
 		return
 	}
 
@@ -231,10 +218,7 @@ func (t *assignedManagedIdentityResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error getting workspace",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error getting workspace", err, state.WorkspaceID.ValueString()))
 		return
 	}
 
@@ -253,9 +237,6 @@ func (t *assignedManagedIdentityResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error removing assigned managed identity",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error removing assigned managed identity", err, state.WorkspaceID.ValueString()))
 	}
 }
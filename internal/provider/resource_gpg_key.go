@@ -2,14 +2,15 @@ package provider
 
 import (
 	"context"
-	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -19,21 +20,25 @@ import (
 // StateVersions, Memberships, Variables, ActivityEvents.
 // Also for now, omitting DirtyState, Locked, CurrentStateVersionID, and CurrentJobID.
 type GPGKeyModel struct {
-	ID           types.String `tfsdk:"id"`
-	LastUpdated  types.String `tfsdk:"last_updated"`
-	CreatedBy    types.String `tfsdk:"created_by"`
-	ASCIIArmor   types.String `tfsdk:"ascii_armor"`
-	Fingerprint  types.String `tfsdk:"fingerprint"`
-	GPGKeyID     types.String `tfsdk:"gpg_key_id"`
-	GroupPath    types.String `tfsdk:"group_path"`
-	ResourcePath types.String `tfsdk:"resource_path"`
+	ID              types.String `tfsdk:"id"`
+	LastUpdated     types.String `tfsdk:"last_updated"`
+	CreatedBy       types.String `tfsdk:"created_by"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+	UpdatedAt       types.String `tfsdk:"updated_at"`
+	MetadataVersion types.String `tfsdk:"metadata_version"`
+	ASCIIArmor      types.String `tfsdk:"ascii_armor"`
+	Fingerprint     types.String `tfsdk:"fingerprint"`
+	GPGKeyID        types.String `tfsdk:"gpg_key_id"`
+	GroupPath       types.String `tfsdk:"group_path"`
+	ResourcePath    types.String `tfsdk:"resource_path"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*gpgKeyResource)(nil)
-	_ resource.ResourceWithConfigure   = (*gpgKeyResource)(nil)
-	_ resource.ResourceWithImportState = (*gpgKeyResource)(nil)
+	_ resource.Resource                 = (*gpgKeyResource)(nil)
+	_ resource.ResourceWithConfigure    = (*gpgKeyResource)(nil)
+	_ resource.ResourceWithImportState  = (*gpgKeyResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*gpgKeyResource)(nil)
 )
 
 // NewGPGKeyResource is a helper function to simplify the provider implementation.
@@ -43,6 +48,7 @@ func NewGPGKeyResource() resource.Resource {
 
 type gpgKeyResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -56,7 +62,7 @@ func (t *gpgKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 	description := "Defines and manages a GPG key."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -72,14 +78,7 @@ func (t *gpgKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				MarkdownDescription: "Timestamp when this GPG key was most recently updated.",
 				Description:         "Timestamp when this GPG key was most recently updated.",
 				Computed:            true,
-			},
-			"created_by": schema.StringAttribute{
-				MarkdownDescription: "The email address of the user or account that created this GPG key.",
-				Description:         "The email address of the user or account that created this GPG key.",
-				Computed:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-				},
+				DeprecationMessage:  lastUpdatedDeprecation,
 			},
 			"ascii_armor": schema.StringAttribute{
 				MarkdownDescription: "The ASCII armored key.",
@@ -109,6 +108,9 @@ func (t *gpgKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 				MarkdownDescription: "Path of the parent group.",
 				Description:         "Path of the parent group.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -123,6 +125,16 @@ func (t *gpgKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, res
 			},
 		},
 	}
+
+	resp.Schema.Attributes["created_by"] = createdByAttribute("GPG key")
+	for name, attribute := range metadataAttributes("GPG key") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *gpgKeyResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_gpg_key", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -132,7 +144,9 @@ func (t *gpgKeyResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *gpgKeyResource) Create(ctx context.Context,
@@ -149,13 +163,10 @@ func (t *gpgKeyResource) Create(ctx context.Context,
 	created, err := t.client.GPGKey.CreateGPGKey(ctx,
 		&ttypes.CreateGPGKeyInput{
 			ASCIIArmor: gpgKey.ASCIIArmor.ValueString(),
-			GroupPath:  gpgKey.GroupPath.ValueString(),
+			GroupPath:  t.paths.resolve(gpgKey.GroupPath.ValueString()),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating GPG key",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating GPG key", err, gpgKey.GroupPath.ValueString()))
 		return
 	}
 
@@ -182,14 +193,11 @@ func (t *gpgKeyResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "GPG key", state.ResourcePath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading GPG key",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading GPG key", err, state.ResourcePath.ValueString()))
 		return
 	}
 
@@ -237,10 +245,7 @@ func (t *gpgKeyResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting GPG key",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting GPG key", err, state.ResourcePath.ValueString()))
 	}
 }
 
@@ -256,13 +261,14 @@ func (t *gpgKeyResource) ImportState(ctx context.Context,
 // It is intended to copy from a struct returned by Tharsis to a Terraform plan or state.
 func (t *gpgKeyResource) copyGPGKey(src ttypes.GPGKey, dest *GPGKeyModel) {
 	dest.ID = types.StringValue(src.Metadata.ID)
-	dest.CreatedBy = types.StringValue(src.CreatedBy)
 	dest.ASCIIArmor = types.StringValue(src.ASCIIArmor)
 	dest.Fingerprint = types.StringValue(src.Fingerprint)
 	dest.GPGKeyID = types.StringValue(src.GPGKeyID)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 	dest.ResourcePath = types.StringValue(src.ResourcePath)
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	dest.CreatedBy = types.StringValue(src.CreatedBy)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
 }
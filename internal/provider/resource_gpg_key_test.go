@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -120,6 +121,8 @@ vF7bYisZMWZogpHZ39zCe8T8zjpZ0xipaOmAhvHKR+p2Tm+OwJL7qjs6dQ==
 					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "id"),
 					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "last_updated"),
 					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "created_by"),
+					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "created_at"),
+					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "updated_at"),
 				),
 			},
 
@@ -129,6 +132,16 @@ vF7bYisZMWZogpHZ39zCe8T8zjpZ0xipaOmAhvHKR+p2Tm+OwJL7qjs6dQ==
 				ImportState:       true,
 				ImportStateVerify: true,
 			},
+			// Find the GPG key with the data source, using a lower-case fingerprint.
+			{
+				Config: testGPGKeyDataSourceConfiguration(createASCIIArmor, strings.ToLower(createFingerprint)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.tharsis_gpg_key.tgk", "id", "tharsis_gpg_key.tgk", "id"),
+					resource.TestCheckResourceAttr("data.tharsis_gpg_key.tgk", "gpg_key_id", createGPGKeyID),
+					resource.TestCheckResourceAttr("data.tharsis_gpg_key.tgk", "resource_path", createResourcePath),
+					resource.TestCheckResourceAttr("data.tharsis_gpg_key.tgk", "ascii_armor", createASCIIArmor),
+				),
+			},
 
 			// Update (which requires replacement) and read back.
 			{
@@ -145,6 +158,8 @@ vF7bYisZMWZogpHZ39zCe8T8zjpZ0xipaOmAhvHKR+p2Tm+OwJL7qjs6dQ==
 					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "id"),
 					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "last_updated"),
 					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "created_by"),
+					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "created_at"),
+					resource.TestCheckResourceAttrSet("tharsis_gpg_key.tgk", "updated_at"),
 				),
 			},
 
@@ -166,3 +181,14 @@ resource "tharsis_gpg_key" "tgk" {
 }
 	`, createRootGroup(testGroupPath, "this is a test root group"), asciiArmor)
 }
+
+func testGPGKeyDataSourceConfiguration(asciiArmor, fingerprint string) string {
+	return fmt.Sprintf(`
+%s
+
+data "tharsis_gpg_key" "tgk" {
+	group_path = tharsis_gpg_key.tgk.group_path
+	fingerprint = %q
+}
+	`, testGPGKeyConfiguration(asciiArmor), fingerprint)
+}
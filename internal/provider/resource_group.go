@@ -2,36 +2,51 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"strings"
-	"time"
 
 	"github.com/aws/smithy-go/ptr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
 
+// groupCountsPageSize is the number of subgroups to request per page when counting a group's descendants.
+const groupCountsPageSize = 100
+
 // GroupModel is the model for a group.
 type GroupModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	ParentPath  types.String `tfsdk:"parent_path"`
-	FullPath    types.String `tfsdk:"full_path"`
-	LastUpdated types.String `tfsdk:"last_updated"`
+	ID                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Description          types.String `tfsdk:"description"`
+	ParentPath           types.String `tfsdk:"parent_path"`
+	FullPath             types.String `tfsdk:"full_path"`
+	LastUpdated          types.String `tfsdk:"last_updated"`
+	CreatedAt            types.String `tfsdk:"created_at"`
+	UpdatedAt            types.String `tfsdk:"updated_at"`
+	MetadataVersion      types.String `tfsdk:"metadata_version"`
+	AllowMove            types.Bool   `tfsdk:"allow_move"`
+	WorkspaceCount       types.Int64  `tfsdk:"workspace_count"`
+	DescendantGroupCount types.Int64  `tfsdk:"descendant_group_count"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*groupResource)(nil)
-	_ resource.ResourceWithConfigure   = (*groupResource)(nil)
-	_ resource.ResourceWithImportState = (*groupResource)(nil)
+	_ resource.Resource                 = (*groupResource)(nil)
+	_ resource.ResourceWithConfigure    = (*groupResource)(nil)
+	_ resource.ResourceWithImportState  = (*groupResource)(nil)
+	_ resource.ResourceWithModifyPlan   = (*groupResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*groupResource)(nil)
 )
 
 // NewGroupResource is a helper function to simplify the provider implementation.
@@ -41,6 +56,7 @@ func NewGroupResource() resource.Resource {
 
 type groupResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -54,7 +70,7 @@ func (t *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 	description := "Defines and manages a group."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -86,8 +102,20 @@ func (t *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				MarkdownDescription: "Full path of the parent namespace.",
 				Description:         "Full path of the parent namespace.",
 				Optional:            true, // A root group has no parent path.
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					// Changing the parent moves the group in place if allow_move is true.
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							var allowMove types.Bool
+							resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("allow_move"), &allowMove)...)
+							resp.RequiresReplace = !allowMove.ValueBool()
+						},
+						"Changing the parent requires replacement unless allow_move is true.",
+						"Changing the parent requires replacement unless `allow_move` is true.",
+					),
 				},
 			},
 			"full_path": schema.StringAttribute{
@@ -102,9 +130,59 @@ func (t *groupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				MarkdownDescription: "Timestamp when this group was most recently updated.",
 				Description:         "Timestamp when this group was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
+			},
+			"allow_move": schema.BoolAttribute{
+				MarkdownDescription: "Whether changing parent_path moves the group to the new parent group in place, " +
+					"keeping everything in it. Otherwise, the group is destroyed and re-created. Defaults to false.",
+				Description: "Whether changing parent_path moves the group to the new parent group in place, " +
+					"keeping everything in it. Otherwise, the group is destroyed and re-created. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"workspace_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of workspaces in the group and its descendant groups.",
+				Description:         "The number of workspaces in the group and its descendant groups.",
+				Computed:            true,
+			},
+			"descendant_group_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of groups below the group, at any depth.",
+				Description:         "The number of groups below the group, at any depth.",
+				Computed:            true,
 			},
 		},
 	}
+
+	for name, attribute := range metadataAttributes("group") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// ModifyPlan marks the full path as unknown when the group will be moved to a new parent.
+func (t *groupResource) ModifyPlan(ctx context.Context,
+	req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse,
+) {
+	// Nothing is moved on create or destroy.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var stateParent, planParent types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("parent_path"), &stateParent)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("parent_path"), &planParent)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !stateParent.Equal(planParent) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("full_path"), types.StringUnknown())...)
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *groupResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_group", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -114,7 +192,9 @@ func (t *groupResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *groupResource) Create(ctx context.Context,
@@ -130,7 +210,7 @@ func (t *groupResource) Create(ctx context.Context,
 	// Create the group.
 	var parentPath *string
 	if group.ParentPath.ValueString() != "" {
-		parentPath = ptr.String(group.ParentPath.ValueString())
+		parentPath = ptr.String(t.paths.resolve(group.ParentPath.ValueString()))
 	}
 	created, err := t.client.Group.CreateGroup(ctx,
 		&ttypes.CreateGroupInput{
@@ -139,10 +219,7 @@ func (t *groupResource) Create(ctx context.Context,
 			ParentPath:  parentPath,
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating group",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating group", err, group.ParentPath.ValueString()))
 		return
 	}
 
@@ -150,6 +227,10 @@ func (t *groupResource) Create(ctx context.Context,
 	// Because the schema uses the Set type rather than the List type, make sure to set all fields.
 	t.copyGroup(*created, &group)
 
+	// A new group is empty.
+	group.WorkspaceCount = types.Int64Value(0)
+	group.DescendantGroupCount = types.Int64Value(0)
+
 	// Set the response state to the fully-populated plan, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, group)...)
 }
@@ -170,18 +251,16 @@ func (t *groupResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "group", state.FullPath.ValueString())
 			return
 		}
-		resp.Diagnostics.AddError(
-			"Error reading group",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading group", err, state.FullPath.ValueString()))
 		return
 	}
 
 	// Copy the from-Tharsis struct to the state.
 	t.copyGroup(*found, &state)
+	resp.Diagnostics.Append(t.copyGroupCounts(ctx, &state)...)
 
 	// Set the refreshed state, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -190,13 +269,31 @@ func (t *groupResource) Read(ctx context.Context,
 func (t *groupResource) Update(ctx context.Context,
 	req resource.UpdateRequest, resp *resource.UpdateResponse,
 ) {
-	// Retrieve values from plan.
-	var plan GroupModel
+	// Retrieve values from plan and state.
+	var plan, state GroupModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// Move the group first if its parent changed.  The plan only gets here with a new parent if allow_move is true.
+	if t.paths.resolve(plan.ParentPath.ValueString()) != t.paths.resolve(state.ParentPath.ValueString()) {
+		var newParentPath *string
+		if plan.ParentPath.ValueString() != "" {
+			newParentPath = ptr.String(t.paths.resolve(plan.ParentPath.ValueString()))
+		}
+		_, err := t.client.Group.MigrateGroup(ctx,
+			&ttypes.MigrateGroupInput{
+				GroupPath:     state.FullPath.ValueString(),
+				NewParentPath: newParentPath,
+			})
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error moving group", err, state.FullPath.ValueString()))
+			return
+		}
+	}
+
 	// Update the group via Tharsis.
 	// The ID is used to find the record to update.
 	// The description is modified.
@@ -206,15 +303,13 @@ func (t *groupResource) Update(ctx context.Context,
 			Description: plan.Description.ValueString(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating group",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating group", err, state.FullPath.ValueString()))
 		return
 	}
 
 	// Copy all fields returned by Tharsis back into the plan.
 	t.copyGroup(*updated, &plan)
+	resp.Diagnostics.Append(t.copyGroupCounts(ctx, &plan)...)
 
 	// Set the response state to the fully-populated plan, with or without error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
@@ -242,10 +337,7 @@ func (t *groupResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting group",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting group", err, state.FullPath.ValueString()))
 	}
 }
 
@@ -284,12 +376,90 @@ func (t *groupResource) copyGroup(src ttypes.Group, dest *GroupModel) {
 	dest.Description = types.StringValue(src.Description)
 	parentPath := t.getParentPath(src.FullPath)
 	if parentPath != "" {
-		dest.ParentPath = types.StringValue(parentPath)
+		dest.ParentPath = t.paths.keep(dest.ParentPath, parentPath)
 	}
 	dest.FullPath = types.StringValue(src.FullPath)
+	if dest.AllowMove.IsNull() || dest.AllowMove.IsUnknown() {
+		dest.AllowMove = types.BoolValue(false)
+	}
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
+}
+
+// copyGroupCounts counts the workspaces and descendant groups of a group.
+// Failing to count them only warns, and leaves them null, since they're just for reporting.
+func (t *groupResource) copyGroupCounts(ctx context.Context, dest *GroupModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	dest.WorkspaceCount = types.Int64Null()
+	dest.DescendantGroupCount = types.Int64Null()
+
+	fullPath := dest.FullPath.ValueString()
+	workspaces, err := t.client.Workspaces.GetWorkspaces(ctx, &ttypes.GetWorkspacesInput{
+		Filter: &ttypes.WorkspaceFilter{
+			GroupPath: &fullPath,
+		},
+		PaginationOptions: &ttypes.PaginationOptions{
+			Limit: ptr.Int32(1),
+		},
+	})
+	if err != nil {
+		diags.AddWarning("Failed to count the workspaces of group "+fullPath, err.Error())
+		return diags
+	}
+	if workspaces.PageInfo != nil {
+		dest.WorkspaceCount = types.Int64Value(int64(workspaces.PageInfo.TotalCount))
+	}
+
+	descendants, err := t.countDescendantGroups(ctx, fullPath)
+	if err != nil {
+		diags.AddWarning("Failed to count the descendant groups of group "+fullPath, err.Error())
+		return diags
+	}
+	dest.DescendantGroupCount = types.Int64Value(descendants)
+
+	return diags
+}
+
+// countDescendantGroups returns the number of groups below a group, at any depth.
+// Groups can only be listed by their parent, so it walks the tree a level at a time.
+func (t *groupResource) countDescendantGroups(ctx context.Context, fullPath string) (int64, error) {
+	var count int64
+	parentPaths := []string{fullPath}
+	for len(parentPaths) > 0 {
+		parentPath := parentPaths[0]
+		parentPaths = parentPaths[1:]
+
+		var cursor *string
+		for {
+			output, err := t.client.Group.GetGroups(ctx, &ttypes.GetGroupsInput{
+				Filter: &ttypes.GroupFilter{
+					ParentPath: ptr.String(parentPath),
+				},
+				PaginationOptions: &ttypes.PaginationOptions{
+					Limit:  ptr.Int32(groupCountsPageSize),
+					Cursor: cursor,
+				},
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to list the subgroups of %s: %w", parentPath, err)
+			}
+
+			for _, group := range output.Groups {
+				count++
+				parentPaths = append(parentPaths, group.FullPath)
+			}
+
+			if output.PageInfo == nil || !output.PageInfo.HasNextPage {
+				break
+			}
+			cursor = &output.PageInfo.Cursor
+		}
+	}
+
+	return count, nil
 }
 
 // getParentPath returns the parent path.
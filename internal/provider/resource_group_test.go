@@ -179,6 +179,51 @@ func TestNestedGroup(t *testing.T) {
 	})
 }
 
+func TestMoveGroup(t *testing.T) {
+	createName := "tmg_name"
+	createDescription := "this is moved-group, a test group to move"
+	createParentPath := testGroupPath
+	movedParentPath := testGroupPath + "/tmg_new_parent"
+	var createdID string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and read back a nested group.
+			{
+				Config: testGroupMoveConfiguration(createName, createDescription, "tharsis_group.root-group.full_path"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_group.moved-group", "parent_path", createParentPath),
+					resource.TestCheckResourceAttr("tharsis_group.moved-group", "full_path", createParentPath+"/"+createName),
+					resource.TestCheckResourceAttrWith("tharsis_group.moved-group", "id", func(value string) error {
+						createdID = value
+						return nil
+					}),
+				),
+			},
+
+			// Move the group to a new parent without replacing it.
+			{
+				Config: testGroupMoveConfiguration(createName, createDescription, "tharsis_group.new-parent.full_path"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_group.moved-group", "parent_path", movedParentPath),
+					resource.TestCheckResourceAttr("tharsis_group.moved-group", "full_path", movedParentPath+"/"+createName),
+					resource.TestCheckResourceAttr("tharsis_group.moved-group", "description", createDescription),
+					resource.TestCheckResourceAttrWith("tharsis_group.moved-group", "id", func(value string) error {
+						if value != createdID {
+							return fmt.Errorf("group was replaced: id changed from %s to %s", createdID, value)
+						}
+						return nil
+					}),
+				),
+			},
+
+			// Destroy should be covered automatically by TestCase.
+
+		},
+	})
+}
+
 func createRootGroup(name, description string) string {
 	return createRootGroupOptionalDescription(name, &description)
 }
@@ -209,3 +254,22 @@ resource "tharsis_group" "nested-group" {
 }
 	`, createRootGroup(testGroupPath, "this is a test root group"), name, description)
 }
+
+func testGroupMoveConfiguration(name, description, parentPath string) string {
+	return fmt.Sprintf(`
+
+%s
+
+resource "tharsis_group" "new-parent" {
+	name = "tmg_new_parent"
+	parent_path = tharsis_group.root-group.full_path
+}
+
+resource "tharsis_group" "moved-group" {
+	name = "%s"
+	description = "%s"
+	parent_path = %s
+	allow_move = true
+}
+	`, createRootGroup(testGroupPath, "this is a test root group"), name, description, parentPath)
+}
@@ -0,0 +1,377 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// GroupTreeModel is the model for a subtree of groups.
+// Groups and GroupIDs are keyed by the path of each group relative to ParentPath, such as "eng/platform".
+type GroupTreeModel struct {
+	ID         types.String `tfsdk:"id"`
+	ParentPath types.String `tfsdk:"parent_path"`
+	Groups     types.Map    `tfsdk:"groups"`
+	GroupIDs   types.Map    `tfsdk:"group_ids"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ resource.Resource                   = (*groupTreeResource)(nil)
+	_ resource.ResourceWithConfigure      = (*groupTreeResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*groupTreeResource)(nil)
+)
+
+// NewGroupTreeResource is a helper function to simplify the provider implementation.
+func NewGroupTreeResource() resource.Resource {
+	return &groupTreeResource{}
+}
+
+type groupTreeResource struct {
+	client *tharsis.Client
+	paths  namespacePaths
+}
+
+// Metadata returns the full name of the resource, including prefix, underscore, instance name.
+func (t *groupTreeResource) Metadata(_ context.Context,
+	_ resource.MetadataRequest, resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_group_tree"
+}
+
+func (t *groupTreeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	description := "Defines and manages a subtree of groups under an existing group. " +
+		"Parent groups are created before their children and deleted after them."
+
+	resp.Schema = schema.Schema{
+		Version:             1,
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the group tree, which is the parent path.",
+				Description:         "String identifier of the group tree, which is the parent path.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"parent_path": schema.StringAttribute{
+				MarkdownDescription: "Full path of the existing group the tree is created under.",
+				Description:         "Full path of the existing group the tree is created under.",
+				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"groups": schema.MapAttribute{
+				MarkdownDescription: "Descriptions of the groups, by path relative to the parent path, such as eng/platform. " +
+					"The parent of each nested group must also be in the map.",
+				Description: "Descriptions of the groups, by path relative to the parent path, such as eng/platform. " +
+					"The parent of each nested group must also be in the map.",
+				ElementType: types.StringType,
+				Required:    true,
+			},
+			"group_ids": schema.MapAttribute{
+				MarkdownDescription: "String identifiers of the groups, by path relative to the parent path.",
+				Description:         "String identifiers of the groups, by path relative to the parent path.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that the group paths are valid and that each nested group's parent is in the tree.
+func (t *groupTreeResource) ValidateConfig(ctx context.Context,
+	req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse,
+) {
+	var config GroupTreeModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Groups.IsUnknown() {
+		return
+	}
+
+	groups := map[string]types.String{}
+	resp.Diagnostics.Append(config.Groups.ElementsAs(ctx, &groups, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, name := range groupTreeOrder(groups, false) {
+		if !validators.IsResourcePath(name) {
+			resp.Diagnostics.AddAttributeError(path.Root("groups"), "Invalid group path",
+				fmt.Sprintf("%q is not a valid relative path of group names.", name))
+			continue
+		}
+
+		parent, _ := groupTreeSplit(name)
+		if _, ok := groups[parent]; parent != "" && !ok {
+			resp.Diagnostics.AddAttributeError(path.Root("groups"), "Missing parent group",
+				fmt.Sprintf("Group %q is nested in %q, which must also be in the tree.", name, parent))
+		}
+	}
+}
+
+// Configure lets the provider implement the ResourceWithConfigure interface.
+func (t *groupTreeResource) Configure(_ context.Context,
+	req resource.ConfigureRequest, _ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
+}
+
+func (t *groupTreeResource) Create(ctx context.Context,
+	req resource.CreateRequest, resp *resource.CreateResponse,
+) {
+	// Retrieve values from plan.
+	var plan GroupTreeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	want := map[string]string{}
+	resp.Diagnostics.Append(plan.Groups.ElementsAs(ctx, &want, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create the groups.
+	ids, err := t.reconcileGroups(ctx, t.paths.resolve(plan.ParentPath.ValueString()),
+		map[string]string{}, map[string]string{}, want)
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating group tree", err, plan.ParentPath.ValueString()))
+	}
+
+	// Set the response state to the plan with whichever groups were created, whether or not there is an error.
+	plan.ID = plan.ParentPath
+	resp.Diagnostics.Append(t.setGroupIDs(ctx, ids, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (t *groupTreeResource) Read(ctx context.Context,
+	req resource.ReadRequest, resp *resource.ReadResponse,
+) {
+	// Get the current state.
+	var state GroupTreeModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	haveIDs := map[string]string{}
+	resp.Diagnostics.Append(state.GroupIDs.ElementsAs(ctx, &haveIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get each group from Tharsis.  Groups that were deleted or moved outside Terraform drop out of the tree.
+	prefix := t.paths.resolve(state.ParentPath.ValueString()) + "/"
+	ids := map[string]string{}
+	descriptions := map[string]string{}
+	for _, id := range haveIDs {
+		found, err := t.client.Group.GetGroup(ctx, &ttypes.GetGroupInput{
+			ID: ptr.String(id),
+		})
+		if err != nil {
+			if tharsis.IsNotFoundError(err) {
+				continue
+			}
+
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error reading group tree", err, state.ParentPath.ValueString()))
+			return
+		}
+
+		if !strings.HasPrefix(found.FullPath, prefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(found.FullPath, prefix)
+		ids[name] = found.Metadata.ID
+		descriptions[name] = found.Description
+	}
+
+	groups, diags := types.MapValueFrom(ctx, types.StringType, descriptions)
+	resp.Diagnostics.Append(diags...)
+	state.Groups = groups
+	resp.Diagnostics.Append(t.setGroupIDs(ctx, ids, &state)...)
+
+	// Set the refreshed state, whether or not there is an error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (t *groupTreeResource) Update(ctx context.Context,
+	req resource.UpdateRequest, resp *resource.UpdateResponse,
+) {
+	// Retrieve values from plan and state.
+	var plan, state GroupTreeModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	haveIDs := map[string]string{}
+	haveDescriptions := map[string]string{}
+	want := map[string]string{}
+	resp.Diagnostics.Append(state.GroupIDs.ElementsAs(ctx, &haveIDs, false)...)
+	resp.Diagnostics.Append(state.Groups.ElementsAs(ctx, &haveDescriptions, false)...)
+	resp.Diagnostics.Append(plan.Groups.ElementsAs(ctx, &want, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create, update and delete groups so the tree matches the plan.
+	ids, err := t.reconcileGroups(ctx, t.paths.resolve(plan.ParentPath.ValueString()), haveIDs, haveDescriptions, want)
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating group tree", err, plan.ParentPath.ValueString()))
+	}
+
+	// Set the response state to the plan with whichever groups now exist, with or without error.
+	resp.Diagnostics.Append(t.setGroupIDs(ctx, ids, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (t *groupTreeResource) Delete(ctx context.Context,
+	req resource.DeleteRequest, resp *resource.DeleteResponse,
+) {
+	// Get the current state.
+	var state GroupTreeModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	haveIDs := map[string]string{}
+	resp.Diagnostics.Append(state.GroupIDs.ElementsAs(ctx, &haveIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete all the groups via Tharsis.
+	if _, err := t.reconcileGroups(ctx, t.paths.resolve(state.ParentPath.ValueString()),
+		haveIDs, map[string]string{}, map[string]string{}); err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting group tree", err, state.ParentPath.ValueString()))
+	}
+}
+
+// reconcileGroups deletes, updates and creates groups under a parent so they match want.
+// The groups are named by relative path, and have and want map those names to IDs and descriptions.
+// Children are deleted before their parents, and parents are created before their children.
+// It returns the IDs of the groups that exist afterward, even if there is an error.
+func (t *groupTreeResource) reconcileGroups(ctx context.Context, parentPath string,
+	haveIDs, haveDescriptions, want map[string]string,
+) (map[string]string, error) {
+	ids := map[string]string{}
+	for name, id := range haveIDs {
+		ids[name] = id
+	}
+
+	// Delete groups first, deepest first, so a parent is empty by the time it's deleted.
+	for _, name := range groupTreeOrder(haveIDs, true) {
+		if _, ok := want[name]; ok {
+			continue
+		}
+
+		err := t.client.Group.DeleteGroup(ctx, &ttypes.DeleteGroupInput{
+			ID: ptr.String(haveIDs[name]),
+		})
+		if err != nil && !tharsis.IsNotFoundError(err) {
+			return ids, fmt.Errorf("failed to delete group %s: %w", name, err)
+		}
+		delete(ids, name)
+	}
+
+	for _, name := range groupTreeOrder(want, false) {
+		description := want[name]
+
+		if id, ok := haveIDs[name]; ok {
+			if description == haveDescriptions[name] {
+				continue
+			}
+
+			if _, err := t.client.Group.UpdateGroup(ctx, &ttypes.UpdateGroupInput{
+				ID:          ptr.String(id),
+				Description: description,
+			}); err != nil {
+				return ids, fmt.Errorf("failed to update group %s: %w", name, err)
+			}
+			continue
+		}
+
+		groupParentPath := parentPath
+		parent, groupName := groupTreeSplit(name)
+		if parent != "" {
+			groupParentPath += "/" + parent
+		}
+
+		created, err := t.client.Group.CreateGroup(ctx, &ttypes.CreateGroupInput{
+			Name:        groupName,
+			Description: description,
+			ParentPath:  ptr.String(groupParentPath),
+		})
+		if err != nil {
+			return ids, fmt.Errorf("failed to create group %s: %w", name, err)
+		}
+		ids[name] = created.Metadata.ID
+	}
+
+	return ids, nil
+}
+
+// setGroupIDs copies the IDs of the groups into a plan or state.
+func (t *groupTreeResource) setGroupIDs(ctx context.Context, ids map[string]string, dest *GroupTreeModel) diag.Diagnostics {
+	value, diags := types.MapValueFrom(ctx, types.StringType, ids)
+	dest.GroupIDs = value
+	return diags
+}
+
+// groupTreeOrder returns the relative paths of the groups in a tree, shallowest first unless deepestFirst is set.
+// Groups at the same depth are in alphabetical order, so groups are always changed in the same order.
+func groupTreeOrder[V any](groups map[string]V, deepestFirst bool) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		iDepth, jDepth := strings.Count(names[i], "/"), strings.Count(names[j], "/")
+		if iDepth != jDepth {
+			return (iDepth > jDepth) == deepestFirst
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// groupTreeSplit splits the relative path of a group in a tree into the path of its parent, if any, and its name.
+func groupTreeSplit(name string) (string, string) {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+
+	return "", name
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestGroupTree(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// A nested group without its parent is rejected.
+			{
+				Config: testGroupTreeConfiguration(`
+		"eng/platform" = "Platform team"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Missing parent group"),
+			},
+
+			// Create and read back a group tree.
+			{
+				Config: testGroupTreeConfiguration(`
+		"eng"          = "Engineering"
+		"eng/platform" = "Platform team"
+		"eng/web"      = "Web team"
+		"ops"          = "Operations"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_group_tree.tgt", "id", testGroupPath),
+					resource.TestCheckResourceAttr("tharsis_group_tree.tgt", "groups.%", "4"),
+					resource.TestCheckResourceAttr("tharsis_group_tree.tgt", "groups.eng/platform", "Platform team"),
+					resource.TestCheckResourceAttr("tharsis_group_tree.tgt", "group_ids.%", "4"),
+					resource.TestCheckResourceAttrSet("tharsis_group_tree.tgt", "group_ids.eng/platform"),
+				),
+			},
+
+			// Update one description, remove a nested group, and add one.
+			{
+				Config: testGroupTreeConfiguration(`
+		"eng"          = "Engineering"
+		"eng/platform" = "Platform engineering"
+		"ops"          = "Operations"
+		"ops/sre"      = "Site reliability"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_group_tree.tgt", "groups.%", "4"),
+					resource.TestCheckResourceAttr("tharsis_group_tree.tgt", "groups.eng/platform", "Platform engineering"),
+					resource.TestCheckNoResourceAttr("tharsis_group_tree.tgt", "group_ids.eng/web"),
+					resource.TestCheckResourceAttrSet("tharsis_group_tree.tgt", "group_ids.ops/sre"),
+				),
+			},
+
+			// Destroy should be covered automatically by TestCase.
+		},
+	})
+}
+
+func Test_groupTreeOrder(t *testing.T) {
+	groups := map[string]string{"b": "", "a/c/d": "", "a": "", "a/c": "", "b/a": ""}
+
+	if got, want := groupTreeOrder(groups, false), []string{"a", "b", "a/c", "b/a", "a/c/d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("shallowest first: got %v, want %v", got, want)
+	}
+	if got, want := groupTreeOrder(groups, true), []string{"a/c/d", "a/c", "b/a", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("deepest first: got %v, want %v", got, want)
+	}
+}
+
+func testGroupTreeConfiguration(groups string) string {
+	return fmt.Sprintf(`
+
+%s
+
+resource "tharsis_group_tree" "tgt" {
+	parent_path = tharsis_group.root-group.full_path
+	groups = {%s
+	}
+}
+	`, createRootGroup(testGroupPath, "this is a test root group"), groups)
+}
@@ -5,15 +5,17 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/aws/smithy-go/ptr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -31,7 +33,14 @@ type ManagedIdentityModel struct {
 	AzureTenantID             types.String `tfsdk:"azure_tenant_id"`
 	TharsisServiceAccountPath types.String `tfsdk:"tharsis_service_account_path"`
 	Subject                   types.String `tfsdk:"subject"`
+	PreviousSubject           types.String `tfsdk:"previous_subject"`
 	LastUpdated               types.String `tfsdk:"last_updated"`
+	CreatedBy                 types.String `tfsdk:"created_by"`
+	CreatedAt                 types.String `tfsdk:"created_at"`
+	UpdatedAt                 types.String `tfsdk:"updated_at"`
+	MetadataVersion           types.String `tfsdk:"metadata_version"`
+	// AccessRules is only managed if set, so it can coexist with tharsis_managed_identity_access_rule.
+	AccessRules types.List `tfsdk:"access_rules"`
 }
 
 // managedIdentityDataInput has all fields required for input to the encoded data string.
@@ -55,11 +64,32 @@ type managedIdentityData struct {
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*managedIdentityResource)(nil)
-	_ resource.ResourceWithConfigure   = (*managedIdentityResource)(nil)
-	_ resource.ResourceWithImportState = (*managedIdentityResource)(nil)
+	_ resource.Resource                   = (*managedIdentityResource)(nil)
+	_ resource.ResourceWithConfigure      = (*managedIdentityResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*managedIdentityResource)(nil)
+	_ resource.ResourceWithImportState    = (*managedIdentityResource)(nil)
+	_ resource.ResourceWithUpgradeState   = (*managedIdentityResource)(nil)
 )
 
+// managedIdentityTypeFields lists the type-specific attributes that each type of managed identity
+// requires, and those it doesn't allow.  Attributes not listed for a type are ignored.
+var managedIdentityTypeFields = map[ttypes.ManagedIdentityType]struct {
+	required  []string
+	forbidden []string
+}{
+	ttypes.ManagedIdentityAWSFederated: {
+		required:  []string{"aws_role"},
+		forbidden: []string{"azure_client_id", "azure_tenant_id"},
+	},
+	ttypes.ManagedIdentityAzureFederated: {
+		required:  []string{"azure_client_id", "azure_tenant_id"},
+		forbidden: []string{"aws_role"},
+	},
+	ttypes.ManagedIdentityTharsisFederated: {
+		required: []string{"tharsis_service_account_path"},
+	},
+}
+
 // NewManagedIdentityResource is a helper function to simplify the provider implementation.
 func NewManagedIdentityResource() resource.Resource {
 	return &managedIdentityResource{}
@@ -67,6 +97,7 @@ func NewManagedIdentityResource() resource.Resource {
 
 type managedIdentityResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -80,7 +111,7 @@ func (t *managedIdentityResource) Schema(_ context.Context, _ resource.SchemaReq
 	description := "Defines and manages a managed identity."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -96,6 +127,13 @@ func (t *managedIdentityResource) Schema(_ context.Context, _ resource.SchemaReq
 				MarkdownDescription: "Type of managed identity: AWS, Azure, or Tharsis.",
 				Description:         "Type of managed identity: AWS, Azure, or Tharsis.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.OneOf(
+						string(ttypes.ManagedIdentityAWSFederated),
+						string(ttypes.ManagedIdentityAzureFederated),
+						string(ttypes.ManagedIdentityTharsisFederated),
+					),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -126,6 +164,9 @@ func (t *managedIdentityResource) Schema(_ context.Context, _ resource.SchemaReq
 				MarkdownDescription: "Full path of the parent group.",
 				Description:         "Full path of the parent group.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -159,13 +200,78 @@ func (t *managedIdentityResource) Schema(_ context.Context, _ resource.SchemaReq
 				Description:         "subject string for AWS. Azure, and Tharsis",
 				Computed:            true,
 			},
+			"previous_subject": schema.StringAttribute{
+				MarkdownDescription: "The subject before it last changed, so cloud-side trust policies can trust both " +
+					"the old and new subjects until the change is rolled out. Null if the subject hasn't changed.",
+				Description: "The subject before it last changed, so cloud-side trust policies can trust both " +
+					"the old and new subjects until the change is rolled out. Null if the subject hasn't changed.",
+				Computed: true,
+			},
 			"last_updated": schema.StringAttribute{
 				MarkdownDescription: "Timestamp when this managed identity was most recently updated.",
 				Description:         "Timestamp when this managed identity was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
 			},
+			"access_rules": inlineAccessRulesAttribute(),
 		},
 	}
+
+	resp.Schema.Attributes["created_by"] = createdByAttribute("managed identity")
+	for name, attribute := range metadataAttributes("managed identity") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// ValidateConfig checks that the type-specific attributes match the type of managed identity.
+func (t *managedIdentityResource) ValidateConfig(ctx context.Context,
+	req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse,
+) {
+	var config ManagedIdentityModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An unknown or invalid type is checked once known, or by the type's own validator.
+	if config.Type.IsUnknown() {
+		return
+	}
+	managedIdentityType := ttypes.ManagedIdentityType(config.Type.ValueString())
+	fields, ok := managedIdentityTypeFields[managedIdentityType]
+	if !ok {
+		return
+	}
+
+	values := map[string]types.String{
+		"aws_role":                     config.AWSRole,
+		"azure_client_id":              config.AzureClientID,
+		"azure_tenant_id":              config.AzureTenantID,
+		"tharsis_service_account_path": config.TharsisServiceAccountPath,
+	}
+
+	// Unknown values may turn out to be either, so they're not checked.
+	for _, name := range fields.required {
+		if value := values[name]; !value.IsUnknown() && value.ValueString() == "" {
+			resp.Diagnostics.AddAttributeError(path.Root(name),
+				"Missing "+name,
+				fmt.Sprintf("%s is required when type is %s.", name, managedIdentityType),
+			)
+		}
+	}
+	for _, name := range fields.forbidden {
+		if value := values[name]; !value.IsUnknown() && value.ValueString() != "" {
+			resp.Diagnostics.AddAttributeError(path.Root(name),
+				"Unexpected "+name,
+				fmt.Sprintf("%s is not allowed when type is %s.", name, managedIdentityType),
+			)
+		}
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *managedIdentityResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_managed_identity", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -175,7 +281,9 @@ func (t *managedIdentityResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *managedIdentityResource) Create(ctx context.Context,
@@ -188,7 +296,7 @@ func (t *managedIdentityResource) Create(ctx context.Context,
 		return
 	}
 
-	encodedData, err := t.encodeDataString(managedIdentity.Type,
+	encodedData, err := t.encodeDataString(
 		managedIdentityDataInput{
 			AWSRole:                   managedIdentity.AWSRole.ValueString(),
 			AzureClientID:             managedIdentity.AzureClientID.ValueString(),
@@ -203,20 +311,29 @@ func (t *managedIdentityResource) Create(ctx context.Context,
 		return
 	}
 
+	// Any access rules are created along with the managed identity.
+	var accessRules []ttypes.ManagedIdentityAccessRuleInput
+	if !managedIdentity.AccessRules.IsNull() {
+		var diags diag.Diagnostics
+		accessRules, diags = getInlineAccessRules(ctx, managedIdentity.AccessRules)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	// Create the managed identity.
 	created, err := t.client.ManagedIdentity.CreateManagedIdentity(ctx,
 		&ttypes.CreateManagedIdentityInput{
 			Type:        ttypes.ManagedIdentityType(managedIdentity.Type.ValueString()),
 			Name:        managedIdentity.Name.ValueString(),
 			Description: managedIdentity.Description.ValueString(),
-			GroupPath:   managedIdentity.GroupPath.ValueString(),
+			GroupPath:   t.paths.resolve(managedIdentity.GroupPath.ValueString()),
 			Data:        encodedData,
+			AccessRules: accessRules,
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating managed identity",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating managed identity", err, managedIdentity.GroupPath.ValueString()))
 		return
 	}
 
@@ -229,6 +346,7 @@ func (t *managedIdentityResource) Create(ctx context.Context,
 		)
 		return
 	}
+	managedIdentity.PreviousSubject = types.StringNull()
 
 	// Set the response state to the fully-populated plan, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, managedIdentity)...)
@@ -250,14 +368,11 @@ func (t *managedIdentityResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "managed identity", state.ResourcePath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading managed identity",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading managed identity", err, state.ResourcePath.ValueString()))
 		return
 	}
 
@@ -270,6 +385,35 @@ func (t *managedIdentityResource) Read(ctx context.Context,
 		return
 	}
 
+	// Refresh the access rules, unless they're equivalent to the ones already in the state.
+	if !state.AccessRules.IsNull() {
+		foundRules, rErr := t.client.ManagedIdentity.GetManagedIdentityAccessRules(ctx, &ttypes.GetManagedIdentityInput{
+			ID: ptr.String(found.Metadata.ID),
+		})
+		if rErr != nil {
+			resp.Diagnostics.AddError(
+				"Error reading managed identity access rules",
+				rErr.Error(),
+			)
+			return
+		}
+
+		haveRules, diags := getInlineAccessRules(ctx, state.AccessRules)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		foundInputs := []ttypes.ManagedIdentityAccessRuleInput{}
+		for _, rule := range foundRules {
+			foundInputs = append(foundInputs, toInlineAccessRuleInput(rule))
+		}
+		if !sameInlineAccessRules(haveRules, foundInputs) {
+			state.AccessRules, diags = toProviderInlineAccessRules(ctx, foundRules)
+			resp.Diagnostics.Append(diags...)
+		}
+	}
+
 	// Set the refreshed state, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -284,7 +428,14 @@ func (t *managedIdentityResource) Update(ctx context.Context,
 		return
 	}
 
-	encodedData, err := t.encodeDataString(plan.Type,
+	// Get the current state for the subject before the update.
+	var state ManagedIdentityModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	encodedData, err := t.encodeDataString(
 		managedIdentityDataInput{
 			AWSRole:                   plan.AWSRole.ValueString(),
 			AzureClientID:             plan.AzureClientID.ValueString(),
@@ -309,10 +460,7 @@ func (t *managedIdentityResource) Update(ctx context.Context,
 			Data:        encodedData,
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating managed identity",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating managed identity", err, plan.ResourcePath.ValueString()))
 		return
 	}
 
@@ -325,6 +473,27 @@ func (t *managedIdentityResource) Update(ctx context.Context,
 		return
 	}
 
+	// Remember the old subject if it changed, until it changes again.
+	plan.PreviousSubject = state.PreviousSubject
+	if !state.Subject.IsNull() && !state.Subject.Equal(plan.Subject) {
+		plan.PreviousSubject = state.Subject
+	}
+
+	// Create and delete access rules so they match the plan.
+	// If they're no longer managed here, existing rules are left alone.
+	if !plan.AccessRules.IsNull() {
+		wantRules, diags := getInlineAccessRules(ctx, plan.AccessRules)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err = t.reconcileAccessRules(ctx, updated.Metadata.ID, wantRules); err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error updating managed identity access rules", err, plan.ResourcePath.ValueString()))
+			return
+		}
+	}
+
 	// Set the response state to the fully-populated plan, with or without error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
@@ -353,10 +522,7 @@ func (t *managedIdentityResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting managed identity",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting managed identity", err, state.ResourcePath.ValueString()))
 	}
 }
 
@@ -364,8 +530,71 @@ func (t *managedIdentityResource) Delete(ctx context.Context,
 func (t *managedIdentityResource) ImportState(ctx context.Context,
 	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
 ) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Get the managed identity by ID or resource path from Tharsis.
+	importByResourcePath(ctx, req, resp, "managed_identity", "managed identity", func(id string) (string, error) {
+		found, err := t.client.ManagedIdentity.GetManagedIdentity(ctx, &ttypes.GetManagedIdentityInput{ID: &id})
+		if err != nil {
+			return "", err
+		}
+		return found.Metadata.ID, nil
+	})
+}
+
+// reconcileAccessRules creates and deletes access rules of a managed identity so they match want.
+// New rules are created before old ones are deleted, so the identity is never left without its rules.
+func (t *managedIdentityResource) reconcileAccessRules(ctx context.Context, managedIdentityID string,
+	want []ttypes.ManagedIdentityAccessRuleInput,
+) error {
+	have, err := t.client.ManagedIdentity.GetManagedIdentityAccessRules(ctx, &ttypes.GetManagedIdentityInput{
+		ID: &managedIdentityID,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Count the rules that already exist, so each wanted rule is matched with at most one of them.
+	existing := map[string][]string{}
+	for _, rule := range have {
+		key := inlineAccessRuleKey(toInlineAccessRuleInput(rule))
+		existing[key] = append(existing[key], rule.Metadata.ID)
+	}
+
+	for _, rule := range want {
+		key := inlineAccessRuleKey(rule)
+		if ids := existing[key]; len(ids) > 0 {
+			existing[key] = ids[1:]
+			continue
+		}
+
+		if _, err = t.client.ManagedIdentity.CreateManagedIdentityAccessRule(ctx,
+			&ttypes.CreateManagedIdentityAccessRuleInput{
+				ManagedIdentityID:         managedIdentityID,
+				Type:                      rule.Type,
+				RunStage:                  rule.RunStage,
+				AllowedUsers:              rule.AllowedUsers,
+				AllowedServiceAccounts:    rule.AllowedServiceAccounts,
+				AllowedTeams:              rule.AllowedTeams,
+				ModuleAttestationPolicies: rule.ModuleAttestationPolicies,
+				VerifyStateLineage:        rule.VerifyStateLineage,
+			}); err != nil {
+			return fmt.Errorf("failed to create access rule: %w", err)
+		}
+	}
+
+	// Whatever is left over is no longer wanted.
+	for _, ids := range existing {
+		for _, id := range ids {
+			err = t.client.ManagedIdentity.DeleteManagedIdentityAccessRule(ctx,
+				&ttypes.DeleteManagedIdentityAccessRuleInput{
+					ID: id,
+				})
+			if err != nil && !tharsis.IsNotFoundError(err) {
+				return fmt.Errorf("failed to delete access rule %s: %w", id, err)
+			}
+		}
+	}
+
+	return nil
 }
 
 // copyManagedIdentity copies the contents of a managed identity.
@@ -381,7 +610,7 @@ func (t *managedIdentityResource) copyManagedIdentity(src ttypes.ManagedIdentity
 	dest.ResourcePath = types.StringValue(src.ResourcePath)
 	dest.Name = types.StringValue(src.Name)
 	dest.Description = types.StringValue(src.Description)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 	if decodedData.AWSRole != nil {
 		dest.AWSRole = types.StringValue(*decodedData.AWSRole)
 	}
@@ -397,47 +626,18 @@ func (t *managedIdentityResource) copyManagedIdentity(src ttypes.ManagedIdentity
 	dest.Subject = types.StringValue(decodedData.Subject)
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	dest.CreatedBy = types.StringValue(src.CreatedBy)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
 
 	return nil
 }
 
-// encodeDataString checks the AWS role, Azure client ID, Azure tenant ID, Tharsis service account path,
-// and subject fields and then marshals them into the appropriate type and base64 encodes that.
-func (t *managedIdentityResource) encodeDataString(managedIdentityType types.String, input managedIdentityDataInput) (string, error) {
-	type2 := ttypes.ManagedIdentityType(managedIdentityType.ValueString())
-
-	// What to check depends on the type of managed identity this is.
-	switch type2 {
-	case ttypes.ManagedIdentityAWSFederated:
-		if input.AWSRole == "" {
-			return "", fmt.Errorf("non-empty role is required for AWS managed identity")
-		}
-		if input.AzureClientID != "" {
-			return "", fmt.Errorf("non-empty client ID is not allowed for AWS managed identity")
-		}
-		if input.AzureTenantID != "" {
-			return "", fmt.Errorf("non-empty tenant ID is not allowed for AWS managed identity")
-		}
-	case ttypes.ManagedIdentityAzureFederated:
-		if input.AWSRole != "" {
-			return "", fmt.Errorf("non-empty role is not allowed for Azure managed identity")
-		}
-		if input.AzureClientID == "" {
-			return "", fmt.Errorf("non-empty client ID is required for Azure managed identity")
-		}
-		if input.AzureTenantID == "" {
-			return "", fmt.Errorf("non-empty tenant ID is required for Azure managed identity")
-		}
-	case ttypes.ManagedIdentityTharsisFederated:
-		if input.TharsisServiceAccountPath == "" {
-			return "", fmt.Errorf("non-empty service account path is required for Tharsis managed identity")
-		}
-	default:
-		return "", fmt.Errorf("invalid managed identity type: %s", type2)
-	}
-
-	// With the checking completed, JSON-encode the fields, taking advantage of omitempty.
+// encodeDataString marshals the AWS role, Azure client ID, Azure tenant ID, and Tharsis service account path
+// into the data string of a managed identity and base64 encodes that.  ValidateConfig has already checked
+// that the fields match the type of managed identity.
+func (t *managedIdentityResource) encodeDataString(input managedIdentityDataInput) (string, error) {
+	// JSON-encode the fields, taking advantage of omitempty.
 	preResult, err := json.Marshal(input)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal managed identity data fields")
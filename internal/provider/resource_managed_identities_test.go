@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -337,3 +338,87 @@ resource "tharsis_managed_identity" "tmi_tharsis" {
 
 	`, createRootGroup(testGroupPath, "this is a test root group"), createType, createName, updatedDescription, updatedTharsisServiceAccountPath)
 }
+
+// TestManagedIdentityMismatchedFields tests that fields not matching the type are rejected before anything is created.
+func TestManagedIdentityMismatchedFields(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testSharedProviderConfiguration() + fmt.Sprintf(`
+
+resource "tharsis_managed_identity" "tmi_mismatched" {
+	type            = "%s"
+	name            = "tmi_mismatched_name"
+	group_path      = "%s"
+	aws_role        = "some-iam-role"
+	azure_client_id = "some-client-id"
+}
+
+	`, ttypes.ManagedIdentityAzureFederated, testGroupPath),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("aws_role is not allowed when type is azure_federated"),
+			},
+		},
+	})
+}
+
+// TestManagedIdentityInlineAccessRules tests managing the access rules of a managed identity along with it.
+func TestManagedIdentityInlineAccessRules(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create a managed identity with its access rules.
+			{
+				Config: testSharedProviderConfiguration() + testManagedIdentityInlineAccessRulesConfiguration("plan"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_managed_identity.tmi_rules", "access_rules.#", "2"),
+					resource.TestCheckResourceAttr("tharsis_managed_identity.tmi_rules", "access_rules.0.run_stage", "plan"),
+					resource.TestCheckResourceAttr("tharsis_managed_identity.tmi_rules", "access_rules.1.type", "module_attestation"),
+				),
+			},
+
+			// Change one of the rules, which replaces it.
+			{
+				Config: testSharedProviderConfiguration() + testManagedIdentityInlineAccessRulesConfiguration("apply"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_managed_identity.tmi_rules", "access_rules.#", "2"),
+					resource.TestCheckResourceAttr("tharsis_managed_identity.tmi_rules", "access_rules.0.run_stage", "apply"),
+				),
+			},
+
+			// Destroy should be covered automatically by TestCase.
+
+		},
+	})
+}
+
+func testManagedIdentityInlineAccessRulesConfiguration(runStage string) string {
+	return fmt.Sprintf(`
+
+%s
+
+resource "tharsis_managed_identity" "tmi_rules" {
+	type        = "%s"
+	name        = "tmi_rules_name"
+	description = "this is tmi_rules, a Tharsis managed identity with inline access rules"
+	group_path  = tharsis_group.root-group.full_path
+	aws_role    = "some-iam-role"
+
+	access_rules = [
+		{
+			type          = "eligible_principals"
+			run_stage     = "%s"
+			allowed_users = []
+		},
+		{
+			type      = "module_attestation"
+			run_stage = "plan"
+			module_attestation_policies = [{
+				public_key = "%s"
+			}]
+		},
+	]
+}
+	`, createRootGroup(testGroupPath, "this is a test root group"), string(ttypes.ManagedIdentityAWSFederated), runStage, dummyPublicKey)
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/smithy-go/ptr"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -11,10 +12,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/martian-cloud/terraform-provider-tharsis/internal/modifiers"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -59,6 +62,7 @@ type ManagedIdentityAccessRuleModel struct {
 	AllowedServiceAccounts    basetypes.SetValue  `tfsdk:"allowed_service_accounts"`
 	AllowedTeams              basetypes.SetValue  `tfsdk:"allowed_teams"`
 	VerifyStateLineage        types.Bool          `tfsdk:"verify_state_lineage"`
+	ValidatePrincipals        types.Bool          `tfsdk:"validate_principals"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
@@ -66,6 +70,7 @@ var (
 	_ resource.Resource                = (*managedIdentityAccessRuleResource)(nil)
 	_ resource.ResourceWithConfigure   = (*managedIdentityAccessRuleResource)(nil)
 	_ resource.ResourceWithImportState = (*managedIdentityAccessRuleResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*managedIdentityAccessRuleResource)(nil)
 )
 
 // NewManagedIdentityAccessRuleResource is a helper function to simplify the provider implementation.
@@ -104,6 +109,9 @@ func (t *managedIdentityAccessRuleResource) Schema(_ context.Context, _ resource
 				MarkdownDescription: "Type of access rule: eligible_principals or module_attestation.",
 				Description:         "Type of access rule: eligible_principals or module_attestation.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.OneOf("eligible_principals", "module_attestation"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -112,6 +120,9 @@ func (t *managedIdentityAccessRuleResource) Schema(_ context.Context, _ resource
 				MarkdownDescription: "Type of job, plan or apply.",
 				Description:         "Type of job, plan or apply.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.OneOf("plan", "apply"),
+				},
 				// Can be updated in place, so no RequiresReplace plan modifier.
 			},
 			"managed_identity_id": schema.StringAttribute{
@@ -161,6 +172,11 @@ func (t *managedIdentityAccessRuleResource) Schema(_ context.Context, _ resource
 				Optional:            true,
 				Computed:            true, // When not passed it, it needs to be set by Create.
 			},
+			"validate_principals": schema.BoolAttribute{
+				MarkdownDescription: "Whether to check when planning that each allowed user, service account and team exists, default is false.",
+				Description:         "Whether to check when planning that each allowed user, service account and team exists, default is false.",
+				Optional:            true,
+			},
 			"module_attestation_policies": schema.ListNestedAttribute{
 				MarkdownDescription: "Used to verify that a module has an in-toto attestation that is signed with the specified public key and an optional predicate type.",
 				Description:         "Used to verify that a module has an in-toto attestation that is signed with the specified public key and an optional predicate type.",
@@ -196,7 +212,100 @@ func (t *managedIdentityAccessRuleResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	t.client = req.ProviderData.(*tharsisProvider).client
+}
+
+// ModifyPlan checks that the allowed principals exist, if validate_principals is true.
+func (t *managedIdentityAccessRuleResource) ModifyPlan(ctx context.Context,
+	req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse,
+) {
+	// Nothing to check on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ManagedIdentityAccessRuleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.ValidatePrincipals.ValueBool() {
+		return
+	}
+
+	principals := []struct {
+		attribute string
+		kind      string
+		values    basetypes.SetValue
+		// exists returns false if there's no such principal.
+		exists func(name string) (bool, error)
+	}{
+		{
+			attribute: "allowed_users",
+			kind:      "user",
+			values:    plan.AllowedUsers,
+			exists: func(name string) (bool, error) {
+				// The API only searches for part of a username or email, so look for an exact match.
+				users, err := searchUsers(ctx, t.client, &name)
+				if err != nil {
+					return false, err
+				}
+				for _, user := range users {
+					if user.Username == name {
+						return true, nil
+					}
+				}
+				return false, nil
+			},
+		},
+		{
+			attribute: "allowed_service_accounts",
+			kind:      "service account",
+			values:    plan.AllowedServiceAccounts,
+			exists: func(name string) (bool, error) {
+				_, err := t.client.ServiceAccount.GetServiceAccount(ctx, &ttypes.GetServiceAccountInput{ID: "trn:service_account:" + name})
+				if tharsis.IsNotFoundError(err) {
+					return false, nil
+				}
+				return err == nil, err
+			},
+		},
+		{
+			attribute: "allowed_teams",
+			kind:      "team",
+			values:    plan.AllowedTeams,
+			exists: func(name string) (bool, error) {
+				_, err := t.client.Team.GetTeam(ctx, &ttypes.GetTeamInput{Name: &name})
+				if tharsis.IsNotFoundError(err) {
+					return false, nil
+				}
+				return err == nil, err
+			},
+		},
+	}
+
+	for _, principal := range principals {
+		for _, element := range principal.values.Elements() {
+			value, ok := element.(types.String)
+			if !ok || value.IsUnknown() || value.IsNull() {
+				continue
+			}
+
+			exists, err := principal.exists(value.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root(principal.attribute).AtSetValue(value),
+					"Error checking allowed "+principal.kind,
+					err.Error(),
+				)
+				continue
+			}
+
+			if !exists {
+				resp.Diagnostics.AddAttributeError(path.Root(principal.attribute).AtSetValue(value),
+					"Allowed "+principal.kind+" not found",
+					fmt.Sprintf("The %s %q does not exist in Tharsis, so it could never use this managed identity.",
+						principal.kind, value.ValueString()),
+				)
+			}
+		}
+	}
 }
 
 func (t *managedIdentityAccessRuleResource) Create(ctx context.Context,
@@ -265,10 +374,7 @@ func (t *managedIdentityAccessRuleResource) Create(ctx context.Context,
 	created, err := t.client.ManagedIdentity.CreateManagedIdentityAccessRule(ctx,
 		&accessRuleInput)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating managed identity access rule",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating managed identity access rule", err, accessRule.ManagedIdentityID.ValueString()))
 		return
 	}
 
@@ -343,14 +449,11 @@ func (t *managedIdentityAccessRuleResource) Read(ctx context.Context,
 
 		// Handle the case that the access rule no longer exists if that fact is reported by returning an error.
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "managed identity access rule", state.ID.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading managed identity access rule",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading managed identity access rule", err, state.ManagedIdentityID.ValueString()))
 		return
 	}
 
@@ -474,10 +577,7 @@ func (t *managedIdentityAccessRuleResource) Update(ctx context.Context,
 
 	updated, err := t.client.ManagedIdentity.UpdateManagedIdentityAccessRule(ctx, toUpdate)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating managed identity access rule",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating managed identity access rule", err, plan.ManagedIdentityID.ValueString()))
 		return
 	}
 
@@ -555,10 +655,7 @@ func (t *managedIdentityAccessRuleResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting managed identity access rule",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting managed identity access rule", err, state.ManagedIdentityID.ValueString()))
 	}
 }
 
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -116,6 +117,29 @@ func TestManagedIdentityAccessRules(t *testing.T) {
 	})
 }
 
+// TestManagedIdentityAccessRulesValidatePrincipals tests that a missing principal is reported when planning.
+func TestManagedIdentityAccessRulesValidatePrincipals(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testSharedProviderConfiguration() +
+					testManagedIdentityAccessRulesConfigurationParent() + `
+
+resource "tharsis_managed_identity_access_rule" "rule03" {
+	type                = "eligible_principals"
+	run_stage           = "plan"
+	managed_identity_id = tharsis_managed_identity.tmiar_parent.id
+	allowed_users       = ["tmiar-no-such-user"]
+	validate_principals = true
+}
+`,
+				ExpectError: regexp.MustCompile("Allowed user not found"),
+			},
+		},
+	})
+}
+
 func testManagedIdentityAccessRulesConfigurationParent() string {
 	parentType := string(ttypes.ManagedIdentityAWSFederated)
 	parentName := "tmiar_parent_name"
@@ -3,15 +3,15 @@ package provider
 import (
 	"context"
 	"reflect"
-	"time"
 
 	"github.com/aws/smithy-go/ptr"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -23,15 +23,20 @@ type ManagedIdentityAliasModel struct {
 	Name            types.String `tfsdk:"name"`
 	GroupPath       types.String `tfsdk:"group_path"`
 	LastUpdated     types.String `tfsdk:"last_updated"`
+	CreatedBy       types.String `tfsdk:"created_by"`
+	CreatedAt       types.String `tfsdk:"created_at"`
+	UpdatedAt       types.String `tfsdk:"updated_at"`
+	MetadataVersion types.String `tfsdk:"metadata_version"`
 	AliasSourceID   types.String `tfsdk:"alias_source_id"`
 	AliasSourcePath types.String `tfsdk:"alias_source_path"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*managedIdentityAliasResource)(nil)
-	_ resource.ResourceWithConfigure   = (*managedIdentityAliasResource)(nil)
-	_ resource.ResourceWithImportState = (*managedIdentityAliasResource)(nil)
+	_ resource.Resource                 = (*managedIdentityAliasResource)(nil)
+	_ resource.ResourceWithConfigure    = (*managedIdentityAliasResource)(nil)
+	_ resource.ResourceWithImportState  = (*managedIdentityAliasResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*managedIdentityAliasResource)(nil)
 )
 
 // NewManagedIdentityAliasResource is a helper function to simplify the provider implementation.
@@ -41,6 +46,7 @@ func NewManagedIdentityAliasResource() resource.Resource {
 
 type managedIdentityAliasResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -53,7 +59,7 @@ func (t *managedIdentityAliasResource) Schema(_ context.Context, _ resource.Sche
 	description := "Defines and manages a managed identity alias."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -85,6 +91,9 @@ func (t *managedIdentityAliasResource) Schema(_ context.Context, _ resource.Sche
 				MarkdownDescription: "Full path of the group where alias will be created.",
 				Description:         "Full path of the group where alias will be created.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -93,6 +102,7 @@ func (t *managedIdentityAliasResource) Schema(_ context.Context, _ resource.Sche
 				MarkdownDescription: "Timestamp when this managed identity alias was most recently updated.",
 				Description:         "Timestamp when this managed identity alias was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
 			},
 			"alias_source_id": schema.StringAttribute{
 				MarkdownDescription: "ID of the managed identity being aliased.",
@@ -113,6 +123,16 @@ func (t *managedIdentityAliasResource) Schema(_ context.Context, _ resource.Sche
 			},
 		},
 	}
+
+	resp.Schema.Attributes["created_by"] = createdByAttribute("managed identity alias")
+	for name, attribute := range metadataAttributes("managed identity alias") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *managedIdentityAliasResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_managed_identity_alias", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -121,7 +141,9 @@ func (t *managedIdentityAliasResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *managedIdentityAliasResource) Create(ctx context.Context,
@@ -163,13 +185,10 @@ func (t *managedIdentityAliasResource) Create(ctx context.Context,
 			Name:            managedIdentityAlias.Name.ValueString(),
 			AliasSourceID:   sourceIdentityID,
 			AliasSourcePath: sourceIdentityPath,
-			GroupPath:       managedIdentityAlias.GroupPath.ValueString(),
+			GroupPath:       t.paths.resolve(managedIdentityAlias.GroupPath.ValueString()),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating managed identity alias",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating managed identity alias", err, managedIdentityAlias.GroupPath.ValueString()))
 		return
 	}
 
@@ -203,14 +222,11 @@ func (t *managedIdentityAliasResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "managed identity alias", state.ResourcePath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading managed identity alias",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading managed identity alias", err, state.ResourcePath.ValueString()))
 		return
 	}
 
@@ -275,19 +291,21 @@ func (t *managedIdentityAliasResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting managed identity alias",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting managed identity alias", err, state.ResourcePath.ValueString()))
 	}
 }
 
 // ImportState helps the provider implement the ResourceWithImportState interface.
 func (t *managedIdentityAliasResource) ImportState(ctx context.Context,
 	req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Get the managed identity alias by ID or resource path from Tharsis.
+	importByResourcePath(ctx, req, resp, "managed_identity", "managed identity alias", func(id string) (string, error) {
+		found, err := t.client.ManagedIdentity.GetManagedIdentity(ctx, &ttypes.GetManagedIdentityInput{ID: &id})
+		if err != nil {
+			return "", err
+		}
+		return found.Metadata.ID, nil
+	})
 }
 
 // copyManagedIdentityAlias copies the contents of a managed identity alias.
@@ -297,11 +315,13 @@ func (t *managedIdentityAliasResource) copyManagedIdentityAlias(src ttypes.Manag
 	dest.ID = types.StringValue(src.Metadata.ID)
 	dest.ResourcePath = types.StringValue(src.ResourcePath)
 	dest.Name = types.StringValue(src.Name)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 	dest.AliasSourceID = types.StringValue(*src.AliasSourceID)
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	dest.CreatedBy = types.StringValue(src.CreatedBy)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
 
 	return nil
 }
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// lastUpdatedDeprecation is the deprecation message of the last_updated attributes.
+const lastUpdatedDeprecation = "Use updated_at instead."
+
+// metadataAttributes returns the schema attributes for when a resource was created,
+// when it was last updated, and the version of its metadata.
+func metadataAttributes(resourceType string) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"created_at": schema.StringAttribute{
+			MarkdownDescription: "Timestamp when this " + resourceType + " was created, in RFC3339 format.",
+			Description:         "Timestamp when this " + resourceType + " was created, in RFC3339 format.",
+			Computed:            true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"updated_at": schema.StringAttribute{
+			MarkdownDescription: "Timestamp when this " + resourceType + " was most recently updated, in RFC3339 format.",
+			Description:         "Timestamp when this " + resourceType + " was most recently updated, in RFC3339 format.",
+			Computed:            true,
+		},
+		"metadata_version": schema.StringAttribute{
+			MarkdownDescription: "The version of this " + resourceType + " in Tharsis, which changes each time it is updated.",
+			Description:         "The version of this " + resourceType + " in Tharsis, which changes each time it is updated.",
+			Computed:            true,
+		},
+	}
+}
+
+// createdByAttribute returns the schema attribute for who created a resource.
+// Only some of the SDK's types say who created them, so it's separate from the other metadata.
+func createdByAttribute(resourceType string) schema.Attribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "The email address of the user or account that created this " + resourceType + ".",
+		Description:         "The email address of the user or account that created this " + resourceType + ".",
+		Computed:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// copyMetadata copies when a resource was created, when it was last updated,
+// and the version of its metadata, from the SDK into a plan or state.
+func copyMetadata(src ttypes.ResourceMetadata, createdAt, updatedAt, metadataVersion *types.String) {
+	*createdAt = formatTimestamp(src.CreationTimestamp)
+	*updatedAt = formatTimestamp(src.LastUpdatedTimestamp)
+	*metadataVersion = types.StringValue(src.Version)
+}
+
+// formatTimestamp formats a timestamp from the SDK in RFC3339 format, or returns null if there isn't one.
+func formatTimestamp(timestamp *time.Time) types.String {
+	if timestamp == nil {
+		return types.StringNull()
+	}
+
+	return types.StringValue(timestamp.Format(time.RFC3339))
+}
+
+// upgradeTimestampsState returns the state upgrader from schema version 1,
+// which stored the named top-level timestamp attributes in RFC850 format rather than RFC3339.
+func upgradeTimestampsState(resourceType string, attributes ...string) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		1: {
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				// Version 2 only added attributes, which are null until the next refresh,
+				// so the prior state can be read directly with the current schema.
+				upgraded, err := req.RawState.Unmarshal(resp.State.Schema.Type().TerraformType(ctx))
+				if err != nil {
+					resp.Diagnostics.AddError("Failed to upgrade "+resourceType+" state", err.Error())
+					return
+				}
+				resp.State.Raw = upgraded
+
+				for _, name := range attributes {
+					var timestamp types.String
+					resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root(name), &timestamp)...)
+					if resp.Diagnostics.HasError() {
+						return
+					}
+
+					if !timestamp.IsNull() && !timestamp.IsUnknown() {
+						timestamp = types.StringValue(convertRFC850Timestamp(timestamp.ValueString()))
+						resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(name), timestamp)...)
+					}
+				}
+			},
+		},
+	}
+}
+
+// convertRFC850Timestamp converts an RFC850 timestamp to RFC3339.
+// A value that isn't an RFC850 timestamp is returned as is, to be replaced when the resource is refreshed.
+func convertRFC850Timestamp(value string) string {
+	timestamp, err := time.Parse(time.RFC850, value)
+	if err != nil {
+		return value
+	}
+
+	return timestamp.Format(time.RFC3339)
+}
@@ -0,0 +1,33 @@
+package provider
+
+import "testing"
+
+func Test_convertRFC850Timestamp(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "RFC850 timestamp in UTC",
+			value: "Tuesday, 14-Mar-23 15:04:05 UTC",
+			want:  "2023-03-14T15:04:05Z",
+		},
+		{
+			name:  "already RFC3339",
+			value: "2023-03-14T15:04:05Z",
+			want:  "2023-03-14T15:04:05Z",
+		},
+		{
+			name:  "empty",
+			value: "",
+			want:  "",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := convertRFC850Timestamp(test.value); got != test.want {
+				t.Errorf("convertRFC850Timestamp(%q) = %q, want %q", test.value, got, test.want)
+			}
+		})
+	}
+}
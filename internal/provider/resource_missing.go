@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// removeMissingResource removes a resource that no longer exists in Tharsis from the state.
+// Resources deleted outside of Terraform are re-created by the next apply, so it warns about
+// the deletion rather than letting it go unnoticed.
+func removeMissingResource(ctx context.Context, resp *resource.ReadResponse, description, name string) {
+	tflog.Warn(ctx, "Removing resource that no longer exists from state", map[string]any{
+		"resource_type": description,
+		"resource":      name,
+	})
+
+	resp.Diagnostics.AddWarning(
+		fmt.Sprintf("The %s no longer exists", description),
+		fmt.Sprintf("The %s %s was not found in Tharsis, so it was removed from the Terraform state. "+
+			"It was probably deleted outside of Terraform, and the next apply will create it again.", description, name),
+	)
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func Test_removeMissingResource(t *testing.T) {
+	resp := &resource.ReadResponse{
+		State: tfsdk.State{
+			Schema: schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{Computed: true},
+				},
+			},
+			Raw: tftypes.NewValue(
+				tftypes.Object{AttributeTypes: map[string]tftypes.Type{"id": tftypes.String}},
+				map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "workspace-id")},
+			),
+		},
+	}
+
+	removeMissingResource(context.Background(), resp, "workspace", "group/workspace")
+
+	if !resp.State.Raw.IsNull() {
+		t.Errorf("removeMissingResource() left the resource in the state: %v", resp.State.Raw)
+	}
+	if resp.Diagnostics.HasError() {
+		t.Errorf("removeMissingResource() added an error: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("removeMissingResource() warnings = %d, want 1", resp.Diagnostics.WarningsCount())
+	}
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// RunApprovalModel is the model for the approval or rejection of a run that is waiting to be applied.
+type RunApprovalModel struct {
+	ID              types.String `tfsdk:"id"`
+	RunID           types.String `tfsdk:"run_id"`
+	Comment         types.String `tfsdk:"comment"`
+	Reject          types.Bool   `tfsdk:"reject"`
+	RejectOnDestroy types.Bool   `tfsdk:"reject_on_destroy"`
+	WorkspacePath   types.String `tfsdk:"workspace_path"`
+	Status          types.String `tfsdk:"status"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ resource.Resource              = (*runApprovalResource)(nil)
+	_ resource.ResourceWithConfigure = (*runApprovalResource)(nil)
+)
+
+// NewRunApprovalResource is a helper function to simplify the provider implementation.
+func NewRunApprovalResource() resource.Resource {
+	return &runApprovalResource{}
+}
+
+type runApprovalResource struct {
+	client *tharsis.Client
+}
+
+// Metadata returns the full name of the resource, including prefix, underscore, instance name.
+func (t *runApprovalResource) Metadata(_ context.Context,
+	_ resource.MetadataRequest, resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_run_approval"
+}
+
+func (t *runApprovalResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	description := "Approves a run that has been planned and is waiting to be applied, which starts the apply, " +
+		"or rejects it, which cancels the run. The run is not waited for."
+
+	resp.Schema = schema.Schema{
+		Version:             1,
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the approval, which is the run ID.",
+				Description:         "String identifier of the approval, which is the run ID.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"run_id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the run to approve or reject.",
+				Description:         "String identifier of the run to approve or reject.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				MarkdownDescription: "A comment recorded with the apply when the run is approved.",
+				Description:         "A comment recorded with the apply when the run is approved.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"reject": schema.BoolAttribute{
+				MarkdownDescription: "Whether to reject the run rather than approve it. Defaults to false.",
+				Description:         "Whether to reject the run rather than approve it. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"reject_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Whether destroying this resource rejects the run if it is still waiting to be applied. " +
+					"Defaults to false.",
+				Description: "Whether destroying this resource rejects the run if it is still waiting to be applied. " +
+					"Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				// Only used on destroy, so it can be changed in place.
+			},
+			"workspace_path": schema.StringAttribute{
+				MarkdownDescription: "Full path of the workspace of the run.",
+				Description:         "Full path of the workspace of the run.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The current status of the run.",
+				Description:         "The current status of the run.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure lets the provider implement the ResourceWithConfigure interface.
+func (t *runApprovalResource) Configure(_ context.Context,
+	req resource.ConfigureRequest, _ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+	t.client = req.ProviderData.(*tharsisProvider).client
+}
+
+func (t *runApprovalResource) Create(ctx context.Context,
+	req resource.CreateRequest, resp *resource.CreateResponse,
+) {
+	// Retrieve values from plan.
+	var plan RunApprovalModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	runID := plan.RunID.ValueString()
+
+	// Only a run that has been planned and not yet applied can be approved or rejected.
+	run, err := t.client.Run.GetRun(ctx, &ttypes.GetRunInput{ID: runID})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading run to approve", err, runID))
+		return
+	}
+	if run.Status != ttypes.RunPlanned {
+		resp.Diagnostics.AddError("Run is not waiting for approval",
+			fmt.Sprintf("Run %s has status %s, but only a run with status %s can be approved or rejected.",
+				runID, run.Status, ttypes.RunPlanned))
+		return
+	}
+
+	if plan.Reject.ValueBool() {
+		run, err = t.client.Run.CancelRun(ctx, &ttypes.CancelRunInput{
+			RunID: runID,
+		})
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error rejecting run", err, runID))
+			return
+		}
+	} else {
+		run, err = t.client.Run.ApplyRun(ctx, &ttypes.ApplyRunInput{
+			RunID:   runID,
+			Comment: plan.Comment.ValueStringPointer(),
+		})
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error approving run", err, runID))
+			return
+		}
+	}
+
+	// Map the response body to the schema and update the plan with the computed attribute values.
+	t.copyRun(*run, &plan)
+
+	// Set the response state to the fully-populated plan, whether or not there is an error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (t *runApprovalResource) Read(ctx context.Context,
+	req resource.ReadRequest, resp *resource.ReadResponse,
+) {
+	// Get the current state.
+	var state RunApprovalModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get the run from Tharsis.
+	run, err := t.client.Run.GetRun(ctx, &ttypes.GetRunInput{ID: state.RunID.ValueString()})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			removeMissingResource(ctx, resp, "run", state.RunID.ValueString())
+			return
+		}
+
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading run", err, state.RunID.ValueString()))
+		return
+	}
+
+	// Copy the from-Tharsis struct to the state.
+	t.copyRun(*run, &state)
+
+	// Set the refreshed state, whether or not there is an error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (t *runApprovalResource) Update(ctx context.Context,
+	req resource.UpdateRequest, resp *resource.UpdateResponse,
+) {
+	// Only reject_on_destroy can change in place, and it's only used on destroy,
+	// so the rest of the state is kept.
+	var plan, state RunApprovalModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.RejectOnDestroy = plan.RejectOnDestroy
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (t *runApprovalResource) Delete(ctx context.Context,
+	req resource.DeleteRequest, resp *resource.DeleteResponse,
+) {
+	// Get the current state.
+	var state RunApprovalModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A run can't be unapproved, so there's nothing to do unless it should be rejected.
+	if !state.RejectOnDestroy.ValueBool() {
+		return
+	}
+	runID := state.RunID.ValueString()
+
+	run, err := t.client.Run.GetRun(ctx, &ttypes.GetRunInput{ID: runID})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			return
+		}
+
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading run to reject", err, runID))
+		return
+	}
+
+	// The run has already been applied or finished, so it's too late to reject it.
+	if run.Status != ttypes.RunPlanned {
+		return
+	}
+
+	if _, err = t.client.Run.CancelRun(ctx, &ttypes.CancelRunInput{
+		RunID: runID,
+	}); err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error rejecting run", err, runID))
+	}
+}
+
+// copyRun copies the run's workspace and status.
+// It is intended to copy from a struct returned by Tharsis to a Terraform plan or state.
+func (t *runApprovalResource) copyRun(src ttypes.Run, dest *RunApprovalModel) {
+	dest.ID = types.StringValue(src.Metadata.ID)
+	dest.WorkspacePath = types.StringValue(src.WorkspacePath)
+	dest.Status = types.StringValue(string(src.Status))
+}
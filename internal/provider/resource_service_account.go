@@ -3,12 +3,13 @@ package provider
 import (
 	"context"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -28,6 +29,10 @@ type ServiceAccountModel struct {
 	Description       types.String           `tfsdk:"description"`
 	GroupPath         types.String           `tfsdk:"group_path"`
 	OIDCTrustPolicies []OIDCTrustPolicyModel `tfsdk:"oidc_trust_policies"`
+	LastUpdated       types.String           `tfsdk:"last_updated"`
+	CreatedAt         types.String           `tfsdk:"created_at"`
+	UpdatedAt         types.String           `tfsdk:"updated_at"`
+	MetadataVersion   types.String           `tfsdk:"metadata_version"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
@@ -44,6 +49,7 @@ func NewServiceAccountResource() resource.Resource {
 
 type serviceAccountResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -91,10 +97,19 @@ func (t *serviceAccountResource) Schema(_ context.Context, _ resource.SchemaRequ
 				Required:            true,
 				// Can be updated in place, so no RequiresReplace plan modifier.
 			},
+			"last_updated": schema.StringAttribute{
+				MarkdownDescription: "Timestamp when this service account was most recently updated.",
+				Description:         "Timestamp when this service account was most recently updated.",
+				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
+			},
 			"group_path": schema.StringAttribute{
 				MarkdownDescription: "Path of the parent group.",
 				Description:         "Path of the parent group.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -111,17 +126,27 @@ func (t *serviceAccountResource) Schema(_ context.Context, _ resource.SchemaRequ
 							MarkdownDescription: "Bound claims for this trust policy.",
 							Description:         "Bound claims for this trust policy.",
 							Required:            true,
+							Validators: []validator.Map{
+								validators.NonEmptyMap(),
+							},
 						},
 						"issuer": schema.StringAttribute{
 							MarkdownDescription: "Issuer for this trust policy.",
 							Description:         "Issuer for this trust policy.",
 							Required:            true,
+							Validators: []validator.String{
+								validators.HTTPSURL(),
+							},
 						},
 					},
 				},
 			},
 		},
 	}
+
+	for name, attribute := range metadataAttributes("service account") {
+		resp.Schema.Attributes[name] = attribute
+	}
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -131,7 +156,9 @@ func (t *serviceAccountResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *serviceAccountResource) Create(ctx context.Context,
@@ -149,14 +176,11 @@ func (t *serviceAccountResource) Create(ctx context.Context,
 		&ttypes.CreateServiceAccountInput{
 			Name:              serviceAccount.Name.ValueString(),
 			Description:       serviceAccount.Description.ValueString(),
-			GroupPath:         serviceAccount.GroupPath.ValueString(),
+			GroupPath:         t.paths.resolve(serviceAccount.GroupPath.ValueString()),
 			OIDCTrustPolicies: t.copyTrustPoliciesToInput(serviceAccount.OIDCTrustPolicies),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating service account",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating service account", err, serviceAccount.GroupPath.ValueString()))
 		return
 	}
 
@@ -184,14 +208,11 @@ func (t *serviceAccountResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "service account", state.ResourcePath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading service account",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading service account", err, state.ResourcePath.ValueString()))
 		return
 	}
 
@@ -222,10 +243,7 @@ func (t *serviceAccountResource) Update(ctx context.Context,
 			OIDCTrustPolicies: t.copyTrustPoliciesToInput(plan.OIDCTrustPolicies),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating service account",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating service account", err, plan.ResourcePath.ValueString()))
 		return
 	}
 
@@ -259,10 +277,7 @@ func (t *serviceAccountResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting service account",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting service account", err, state.ResourcePath.ValueString()))
 	}
 }
 
@@ -270,8 +285,14 @@ func (t *serviceAccountResource) Delete(ctx context.Context,
 func (t *serviceAccountResource) ImportState(ctx context.Context,
 	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
 ) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Get the service account by ID or resource path from Tharsis.
+	importByResourcePath(ctx, req, resp, "service_account", "service account", func(id string) (string, error) {
+		found, err := t.client.ServiceAccount.GetServiceAccount(ctx, &ttypes.GetServiceAccountInput{ID: id})
+		if err != nil {
+			return "", err
+		}
+		return found.Metadata.ID, nil
+	})
 }
 
 // copyServiceAccount copies the contents of a service account.
@@ -281,7 +302,7 @@ func (t *serviceAccountResource) copyServiceAccount(src ttypes.ServiceAccount, d
 	dest.ResourcePath = types.StringValue(src.ResourcePath)
 	dest.Name = types.StringValue(src.Name)
 	dest.Description = types.StringValue(src.Description)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 
 	newPolicies := []OIDCTrustPolicyModel{}
 	for _, trustPolicy := range src.OIDCTrustPolicies {
@@ -295,6 +316,10 @@ func (t *serviceAccountResource) copyServiceAccount(src ttypes.ServiceAccount, d
 		newPolicies = append(newPolicies, newPolicy)
 	}
 	dest.OIDCTrustPolicies = newPolicies
+
+	// Must use time value from SDK/API.  Using time.Now() is not reliable.
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
 }
 
 // copyTrustPoliciesToInput copies a slice of OIDCTrustPolicyModel to a slice of ttypes.OIDCTrustPolicyInput.
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -17,6 +18,18 @@ func TestServiceAccount(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
+			// Trust policies with an insecure issuer or no bound claims are rejected.
+			{
+				Config:      testServiceAccountConfigurationInvalidTrustPolicy(`{bound_claims = {"sub" = "x"}, issuer = "http://issuer/"}`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Invalid URL"),
+			},
+			{
+				Config:      testServiceAccountConfigurationInvalidTrustPolicy(`{bound_claims = {}, issuer = "https://issuer/"}`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Empty map"),
+			},
+
 			// Create and read back a service account.
 			{
 				Config: testServiceAccountConfigurationCreate(),
@@ -29,6 +42,7 @@ func TestServiceAccount(t *testing.T) {
 
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("tharsis_service_account.tsa", "id"),
+					resource.TestCheckResourceAttrSet("tharsis_service_account.tsa", "last_updated"),
 				),
 			},
 
@@ -39,6 +53,14 @@ func TestServiceAccount(t *testing.T) {
 				ImportStateVerify: true,
 			},
 
+			// Import the state by resource path.
+			{
+				ResourceName:      "tharsis_service_account.tsa",
+				ImportStateId:     createResourcePath,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+
 			// Update and read.
 			{
 				Config: testServiceAccountConfigurationUpdate(),
@@ -103,3 +125,17 @@ resource "tharsis_service_account" "tsa" {
 		updateTrustPolicyBoundClaimKey, updateTrustPolicyBoundClaimValue, updateTrustPolicyIssuer,
 	)
 }
+
+func testServiceAccountConfigurationInvalidTrustPolicy(trustPolicy string) string {
+	return fmt.Sprintf(`
+
+%s
+
+resource "tharsis_service_account" "tsa" {
+	name = "tsa_name"
+	description = "this is tsa, a test service account"
+	group_path = tharsis_group.root-group.full_path
+	oidc_trust_policies = [%s]
+}
+	`, createRootGroup(testGroupPath, "this is a test root group"), trustPolicy)
+}
@@ -2,15 +2,16 @@ package provider
 
 import (
 	"context"
-	"time"
+	"fmt"
 
 	"github.com/aws/smithy-go/ptr"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -25,14 +26,19 @@ type TerraformModuleModel struct {
 	RegistryNamespace types.String `tfsdk:"registry_namespace"`
 	RepositoryURL     types.String `tfsdk:"repository_url"`
 	LastUpdated       types.String `tfsdk:"last_updated"`
+	CreatedAt         types.String `tfsdk:"created_at"`
+	UpdatedAt         types.String `tfsdk:"updated_at"`
+	MetadataVersion   types.String `tfsdk:"metadata_version"`
 	Private           types.Bool   `tfsdk:"private"`
+	LatestVersion     types.String `tfsdk:"latest_version"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*terraformModuleResource)(nil)
-	_ resource.ResourceWithConfigure   = (*terraformModuleResource)(nil)
-	_ resource.ResourceWithImportState = (*terraformModuleResource)(nil)
+	_ resource.Resource                 = (*terraformModuleResource)(nil)
+	_ resource.ResourceWithConfigure    = (*terraformModuleResource)(nil)
+	_ resource.ResourceWithImportState  = (*terraformModuleResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*terraformModuleResource)(nil)
 )
 
 // NewTerraformModuleResource is a helper function to simplify the provider implementation.
@@ -42,6 +48,7 @@ func NewTerraformModuleResource() resource.Resource {
 
 type terraformModuleResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -55,7 +62,7 @@ func (t *terraformModuleResource) Schema(_ context.Context, _ resource.SchemaReq
 	description := "Defines and manages a Terraform module."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -87,6 +94,9 @@ func (t *terraformModuleResource) Schema(_ context.Context, _ resource.SchemaReq
 				MarkdownDescription: "The group path for this module.",
 				Description:         "The group path for this module.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -119,14 +129,29 @@ func (t *terraformModuleResource) Schema(_ context.Context, _ resource.SchemaReq
 				Optional:            true,
 				// Can be updated in place, so no RequiresReplace plan modifier.
 			},
+			"latest_version": schema.StringAttribute{
+				MarkdownDescription: "The latest version of this module in the registry, or null if no version has been published.",
+				Description:         "The latest version of this module in the registry, or null if no version has been published.",
+				Computed:            true,
+			},
 			// Keep this:
 			"last_updated": schema.StringAttribute{
 				MarkdownDescription: "Timestamp when this terraform module was most recently updated.",
 				Description:         "Timestamp when this terraform module was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
 			},
 		},
 	}
+
+	for name, attribute := range metadataAttributes("Terraform module") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *terraformModuleResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_terraform_module", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -136,7 +161,9 @@ func (t *terraformModuleResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *terraformModuleResource) Create(ctx context.Context,
@@ -153,20 +180,19 @@ func (t *terraformModuleResource) Create(ctx context.Context,
 		&ttypes.CreateTerraformModuleInput{
 			Name:          terraformModule.Name.ValueString(),
 			System:        terraformModule.System.ValueString(),
-			GroupPath:     terraformModule.GroupPath.ValueString(),
+			GroupPath:     t.paths.resolve(terraformModule.GroupPath.ValueString()),
 			RepositoryURL: terraformModule.RepositoryURL.ValueString(),
 			Private:       terraformModule.Private.ValueBool(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating Terraform module",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating Terraform module", err, terraformModule.GroupPath.ValueString()))
 		return
 	}
 
 	// Map the response body to the schema and update the plan with the computed attribute values.
+	// A new module has no versions yet.
 	t.copyTerraformModule(*created, &terraformModule)
+	terraformModule.LatestVersion = types.StringNull()
 
 	// Set the response state to the fully-populated plan, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, terraformModule)...)
@@ -188,19 +214,21 @@ func (t *terraformModuleResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "Terraform module", state.ResourcePath.ValueString())
 			return
 		}
-		resp.Diagnostics.AddError(
-			"Error reading Terraform module",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading Terraform module", err, state.ResourcePath.ValueString()))
 		return
 	}
 
 	// Copy the from-Tharsis struct to the state.
 	t.copyTerraformModule(*found, &state)
 
+	state.LatestVersion, err = latestModuleVersion(ctx, t.client, *found)
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading latest Terraform module version", err, state.ResourcePath.ValueString()))
+	}
+
 	// Set the refreshed state, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -224,16 +252,18 @@ func (t *terraformModuleResource) Update(ctx context.Context,
 			Private:       ptr.Bool(plan.Private.ValueBool()),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Terraform module",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating Terraform module", err, plan.ResourcePath.ValueString()))
 		return
 	}
 
 	// Copy all fields returned by Tharsis back into the plan.
 	t.copyTerraformModule(*updated, &plan)
 
+	plan.LatestVersion, err = latestModuleVersion(ctx, t.client, *updated)
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading latest Terraform module version", err, plan.ResourcePath.ValueString()))
+	}
+
 	// Set the response state to the fully-populated plan, with or without error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
@@ -259,10 +289,7 @@ func (t *terraformModuleResource) Delete(ctx context.Context,
 			resp.State.RemoveResource(ctx)
 			return
 		}
-		resp.Diagnostics.AddError(
-			"Error deleting Terraform module",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting Terraform module", err, state.ResourcePath.ValueString()))
 	}
 }
 
@@ -270,8 +297,14 @@ func (t *terraformModuleResource) Delete(ctx context.Context,
 func (t *terraformModuleResource) ImportState(ctx context.Context,
 	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
 ) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Get the Terraform module by ID or resource path from Tharsis.
+	importByResourcePath(ctx, req, resp, "terraform_module", "Terraform module", func(id string) (string, error) {
+		found, err := t.client.TerraformModule.GetModule(ctx, &ttypes.GetTerraformModuleInput{ID: &id})
+		if err != nil {
+			return "", err
+		}
+		return found.Metadata.ID, nil
+	})
 }
 
 // copyTerraformModule copies the contents of a Terraform module.
@@ -280,12 +313,30 @@ func (t *terraformModuleResource) copyTerraformModule(src ttypes.TerraformModule
 	dest.ID = types.StringValue(src.Metadata.ID)
 	dest.Name = types.StringValue(src.Name)
 	dest.System = types.StringValue(src.System)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 	dest.ResourcePath = types.StringValue(src.ResourcePath)
 	dest.RegistryNamespace = types.StringValue(src.RegistryNamespace)
 	dest.RepositoryURL = types.StringValue(src.RepositoryURL)
 	dest.Private = types.BoolValue(src.Private)
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
+}
+
+// latestModuleVersion returns the latest version of a module in the registry, or null if it has no versions.
+func latestModuleVersion(ctx context.Context, client *tharsis.Client, module ttypes.TerraformModule) (types.String, error) {
+	// Without a version, the registry returns the latest one.
+	modulePath := fmt.Sprintf("%s/%s/%s", module.RegistryNamespace, module.Name, module.System)
+	version, err := client.TerraformModuleVersion.GetModuleVersion(ctx, &ttypes.GetTerraformModuleVersionInput{
+		ModulePath: &modulePath,
+	})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			return types.StringNull(), nil
+		}
+		return types.StringNull(), err
+	}
+
+	return types.StringValue(version.Version), nil
 }
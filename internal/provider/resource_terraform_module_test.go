@@ -48,6 +48,20 @@ func TestTerraformModule(t *testing.T) {
 				ImportStateVerify: true,
 			},
 
+			// Find the module with the data sources.
+			{
+				Config: testTerraformModuleDataSourceConfiguration(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.tharsis_terraform_module.ttm", "id", "tharsis_terraform_module.ttm", "id"),
+					resource.TestCheckResourceAttr("data.tharsis_terraform_module.ttm", "group_path", createGroupPath),
+					resource.TestCheckResourceAttr("data.tharsis_terraform_module.ttm", "repository_url", createRepositoryURL),
+					resource.TestCheckResourceAttr("data.tharsis_terraform_module.ttm", "private", strconv.FormatBool(createPrivate)),
+					resource.TestCheckResourceAttr("data.tharsis_terraform_modules.ttm", "modules.#", "1"),
+					resource.TestCheckResourceAttrPair("data.tharsis_terraform_modules.ttm", "modules.0.id", "tharsis_terraform_module.ttm", "id"),
+					resource.TestCheckResourceAttr("data.tharsis_terraform_modules.ttm", "modules.0.name", createName),
+				),
+			},
+
 			// Update and read.
 			{
 				Config: testTerraformModuleConfigurationUpdate(),
@@ -112,3 +126,21 @@ resource "tharsis_terraform_module" "ttm" {
 }
 	`, createRootGroup(testGroupPath, "this is a test root group"), createName, createSystem, updateRepositoryURL, updatePrivate)
 }
+
+func testTerraformModuleDataSourceConfiguration() string {
+	return fmt.Sprintf(`
+%s
+
+data "tharsis_terraform_module" "ttm" {
+	registry_namespace = tharsis_terraform_module.ttm.registry_namespace
+	name = tharsis_terraform_module.ttm.name
+	system = tharsis_terraform_module.ttm.system
+}
+
+data "tharsis_terraform_modules" "ttm" {
+	search = tharsis_terraform_module.ttm.name
+	group_path = tharsis_terraform_module.ttm.group_path
+	private = true
+}
+	`, testTerraformModuleConfigurationCreate())
+}
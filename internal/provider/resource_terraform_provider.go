@@ -2,14 +2,14 @@ package provider
 
 import (
 	"context"
-	"time"
 
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -18,6 +18,9 @@ import (
 type TerraformProviderModel struct {
 	ID                types.String `tfsdk:"id"`
 	LastUpdated       types.String `tfsdk:"last_updated"`
+	CreatedAt         types.String `tfsdk:"created_at"`
+	UpdatedAt         types.String `tfsdk:"updated_at"`
+	MetadataVersion   types.String `tfsdk:"metadata_version"`
 	Name              types.String `tfsdk:"name"`
 	GroupPath         types.String `tfsdk:"group_path"`
 	ResourcePath      types.String `tfsdk:"resource_path"`
@@ -28,9 +31,10 @@ type TerraformProviderModel struct {
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*terraformProviderResource)(nil)
-	_ resource.ResourceWithConfigure   = (*terraformProviderResource)(nil)
-	_ resource.ResourceWithImportState = (*terraformProviderResource)(nil)
+	_ resource.Resource                 = (*terraformProviderResource)(nil)
+	_ resource.ResourceWithConfigure    = (*terraformProviderResource)(nil)
+	_ resource.ResourceWithImportState  = (*terraformProviderResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*terraformProviderResource)(nil)
 )
 
 // NewTerraformProviderResource is a helper function to simplify the provider implementation.
@@ -40,6 +44,7 @@ func NewTerraformProviderResource() resource.Resource {
 
 type terraformProviderResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -53,7 +58,7 @@ func (t *terraformProviderResource) Schema(_ context.Context, _ resource.SchemaR
 	description := "Defines and manages a Terraform provider."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -77,6 +82,9 @@ func (t *terraformProviderResource) Schema(_ context.Context, _ resource.SchemaR
 				MarkdownDescription: "The path of the group where this Terraform provider resides.",
 				Description:         "The path of the group where this Terraform provider resides.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -113,9 +121,19 @@ func (t *terraformProviderResource) Schema(_ context.Context, _ resource.SchemaR
 				MarkdownDescription: "Timestamp when this Terraform provider was most recently updated.",
 				Description:         "Timestamp when this Terraform provider was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
 			},
 		},
 	}
+
+	for name, attribute := range metadataAttributes("Terraform provider") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *terraformProviderResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_terraform_provider", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -125,7 +143,9 @@ func (t *terraformProviderResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *terraformProviderResource) Create(ctx context.Context,
@@ -142,15 +162,12 @@ func (t *terraformProviderResource) Create(ctx context.Context,
 	created, err := t.client.TerraformProvider.CreateProvider(ctx,
 		&ttypes.CreateTerraformProviderInput{
 			Name:          terraformProvider.Name.ValueString(),
-			GroupPath:     terraformProvider.GroupPath.ValueString(),
+			GroupPath:     t.paths.resolve(terraformProvider.GroupPath.ValueString()),
 			RepositoryURL: terraformProvider.RepositoryURL.ValueString(),
 			Private:       terraformProvider.Private.ValueBool(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating Terraform provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating Terraform provider", err, terraformProvider.GroupPath.ValueString()))
 		return
 	}
 
@@ -177,14 +194,11 @@ func (t *terraformProviderResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "Terraform provider", state.ResourcePath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading Terraform provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading Terraform provider", err, state.ResourcePath.ValueString()))
 		return
 	}
 
@@ -214,10 +228,7 @@ func (t *terraformProviderResource) Update(ctx context.Context,
 			Private:       plan.Private.ValueBool(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating Terraform provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating Terraform provider", err, plan.ResourcePath.ValueString()))
 		return
 	}
 
@@ -251,10 +262,7 @@ func (t *terraformProviderResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting Terraform provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting Terraform provider", err, state.ResourcePath.ValueString()))
 	}
 }
 
@@ -262,8 +270,14 @@ func (t *terraformProviderResource) Delete(ctx context.Context,
 func (t *terraformProviderResource) ImportState(ctx context.Context,
 	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
 ) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Get the Terraform provider by ID or resource path from Tharsis.
+	importByResourcePath(ctx, req, resp, "terraform_provider", "Terraform provider", func(id string) (string, error) {
+		found, err := t.client.TerraformProvider.GetProvider(ctx, &ttypes.GetTerraformProviderInput{ID: id})
+		if err != nil {
+			return "", err
+		}
+		return found.Metadata.ID, nil
+	})
 }
 
 // copyTerraformProvider copies the contents of a Terraform provider.
@@ -271,12 +285,13 @@ func (t *terraformProviderResource) ImportState(ctx context.Context,
 func (t *terraformProviderResource) copyTerraformProvider(src ttypes.TerraformProvider, dest *TerraformProviderModel) {
 	dest.ID = types.StringValue(src.Metadata.ID)
 	dest.Name = types.StringValue(src.Name)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 	dest.ResourcePath = types.StringValue(src.ResourcePath)
 	dest.RegistryNamespace = types.StringValue(src.RegistryNamespace)
 	dest.RepositoryURL = types.StringValue(src.RepositoryURL)
 	dest.Private = types.BoolValue(src.Private)
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
 }
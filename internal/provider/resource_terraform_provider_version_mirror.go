@@ -0,0 +1,346 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// defaultMirrorRegistryHostname is the registry that provider versions are mirrored from by default.
+const defaultMirrorRegistryHostname = "registry.terraform.io"
+
+// TerraformProviderVersionMirrorModel is the model for a mirrored version of a Terraform provider.
+type TerraformProviderVersionMirrorModel struct {
+	ID                types.String `tfsdk:"id"`
+	GroupPath         types.String `tfsdk:"group_path"`
+	RegistryHostname  types.String `tfsdk:"registry_hostname"`
+	RegistryNamespace types.String `tfsdk:"registry_namespace"`
+	Type              types.String `tfsdk:"type"`
+	SemanticVersion   types.String `tfsdk:"semantic_version"`
+	Platforms         types.List   `tfsdk:"platforms"`
+	CreatedAt         types.String `tfsdk:"created_at"`
+	UpdatedAt         types.String `tfsdk:"updated_at"`
+	MetadataVersion   types.String `tfsdk:"metadata_version"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ resource.Resource                = (*terraformProviderVersionMirrorResource)(nil)
+	_ resource.ResourceWithConfigure   = (*terraformProviderVersionMirrorResource)(nil)
+	_ resource.ResourceWithImportState = (*terraformProviderVersionMirrorResource)(nil)
+)
+
+// NewTerraformProviderVersionMirrorResource is a helper function to simplify the provider implementation.
+func NewTerraformProviderVersionMirrorResource() resource.Resource {
+	return &terraformProviderVersionMirrorResource{}
+}
+
+type terraformProviderVersionMirrorResource struct {
+	client *tharsis.Client
+	paths  namespacePaths
+}
+
+// Metadata returns the full name of the resource, including prefix, underscore, instance name.
+func (t *terraformProviderVersionMirrorResource) Metadata(_ context.Context,
+	_ resource.MetadataRequest, resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_terraform_provider_version_mirror"
+}
+
+func (t *terraformProviderVersionMirrorResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	description := "Defines and manages a version of an upstream Terraform provider that is mirrored in a group. " +
+		"The packages for each platform are mirrored when Terraform first installs them through the group's mirror, " +
+		"or can be uploaded in advance for air-gapped groups with the Tharsis CLI."
+
+	resp.Schema = schema.Schema{
+		Version:             1,
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the provider version mirror.",
+				Description:         "String identifier of the provider version mirror.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"group_path": schema.StringAttribute{
+				MarkdownDescription: "Path of the group the provider version is mirrored in.",
+				Description:         "Path of the group the provider version is mirrored in.",
+				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"registry_hostname": schema.StringAttribute{
+				MarkdownDescription: "Hostname of the registry the provider is mirrored from. Defaults to registry.terraform.io.",
+				Description:         "Hostname of the registry the provider is mirrored from. Defaults to registry.terraform.io.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(defaultMirrorRegistryHostname),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"registry_namespace": schema.StringAttribute{
+				MarkdownDescription: "Namespace of the provider in its registry, such as hashicorp.",
+				Description:         "Namespace of the provider in its registry, such as hashicorp.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Type of the provider, such as aws.",
+				Description:         "Type of the provider, such as aws.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"semantic_version": schema.StringAttribute{
+				MarkdownDescription: "The version of the provider to mirror, such as 5.0.0.",
+				Description:         "The version of the provider to mirror, such as 5.0.0.",
+				Required:            true,
+				Validators: []validator.String{
+					validators.Semver(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"platforms": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "The platforms whose packages have been mirrored, such as linux_amd64. Read-only, since the SDK can't upload packages; they're mirrored by Terraform or the Tharsis CLI.",
+				Description:         "The platforms whose packages have been mirrored, such as linux_amd64. Read-only, since the SDK can't upload packages; they're mirrored by Terraform or the Tharsis CLI.",
+				Computed:            true,
+			},
+		},
+	}
+
+	for name, attribute := range metadataAttributes("provider version mirror") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// Configure lets the provider implement the ResourceWithConfigure interface.
+func (t *terraformProviderVersionMirrorResource) Configure(_ context.Context,
+	req resource.ConfigureRequest, _ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
+}
+
+func (t *terraformProviderVersionMirrorResource) Create(ctx context.Context,
+	req resource.CreateRequest, resp *resource.CreateResponse,
+) {
+	// Retrieve values from the provider version mirror.
+	var mirror TerraformProviderVersionMirrorModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &mirror)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create the provider version mirror.
+	created, err := t.client.TerraformProviderVersionMirror.CreateProviderVersionMirror(ctx,
+		&ttypes.CreateTerraformProviderVersionMirrorInput{
+			GroupPath:         t.paths.resolve(mirror.GroupPath.ValueString()),
+			RegistryHostname:  mirror.RegistryHostname.ValueString(),
+			RegistryNamespace: mirror.RegistryNamespace.ValueString(),
+			Type:              mirror.Type.ValueString(),
+			SemanticVersion:   mirror.SemanticVersion.ValueString(),
+		})
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating Terraform provider version mirror", err,
+			mirror.GroupPath.ValueString()))
+		return
+	}
+
+	// Map the response body to the schema and update the plan with the computed attribute values.
+	t.copyProviderVersionMirror(*created, &mirror)
+	resp.Diagnostics.Append(t.setPlatforms(ctx, &mirror)...)
+
+	// Set the response state to the fully-populated plan, whether or not there is an error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, mirror)...)
+}
+
+func (t *terraformProviderVersionMirrorResource) Read(ctx context.Context,
+	req resource.ReadRequest, resp *resource.ReadResponse,
+) {
+	// Get the current state.
+	var state TerraformProviderVersionMirrorModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get the provider version mirror from Tharsis.
+	found, err := t.client.TerraformProviderVersionMirror.GetProviderVersionMirror(ctx,
+		&ttypes.GetTerraformProviderVersionMirrorInput{
+			ID: state.ID.ValueString(),
+		})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			removeMissingResource(ctx, resp, "Terraform provider version mirror", state.ID.ValueString())
+			return
+		}
+
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading Terraform provider version mirror", err,
+			state.ID.ValueString()))
+		return
+	}
+
+	// Copy the from-Tharsis struct to the state.
+	t.copyProviderVersionMirror(*found, &state)
+	resp.Diagnostics.Append(t.setPlatforms(ctx, &state)...)
+
+	// Set the refreshed state, whether or not there is an error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (t *terraformProviderVersionMirrorResource) Update(_ context.Context,
+	_ resource.UpdateRequest, resp *resource.UpdateResponse,
+) {
+	// This method must exist to comply with the required interfaces,
+	// but all input attributes have the RequiresReplace plan modifier,
+	// so there's nothing for it to do.  It should never be called.
+	// If it is, it should error out.
+
+	resp.Diagnostics.AddError(
+		"Error updating Terraform provider version mirror.",
+		"Terraform provider version mirror should never be updated in place.",
+	)
+}
+
+func (t *terraformProviderVersionMirrorResource) Delete(ctx context.Context,
+	req resource.DeleteRequest, resp *resource.DeleteResponse,
+) {
+	// Get the current state.
+	var state TerraformProviderVersionMirrorModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete the provider version mirror, along with its mirrored packages, via Tharsis.
+	err := t.client.TerraformProviderVersionMirror.DeleteProviderVersionMirror(ctx,
+		&ttypes.DeleteTerraformProviderVersionMirrorInput{
+			ID: state.ID.ValueString(),
+		})
+	if err != nil {
+
+		// Handle the case that the provider version mirror no longer exists.
+		if tharsis.IsNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting Terraform provider version mirror", err,
+			state.ID.ValueString()))
+	}
+}
+
+// ImportState helps the provider implement the ResourceWithImportState interface.
+func (t *terraformProviderVersionMirrorResource) ImportState(ctx context.Context,
+	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
+) {
+	// The API doesn't return the group path, so the mirror is imported by its address in the group:
+	// <group_path>/<registry_hostname>/<registry_namespace>/<type>/<semantic_version>
+	parts := strings.Split(req.ID, "/")
+	if len(parts) < 5 {
+		resp.Diagnostics.AddError(
+			"Invalid import ID: "+req.ID,
+			"Expected <group_path>/<registry_hostname>/<registry_namespace>/<type>/<semantic_version>.",
+		)
+		return
+	}
+	groupPath := strings.Join(parts[:len(parts)-4], "/")
+
+	found, err := t.client.TerraformProviderVersionMirror.GetProviderVersionMirrorByAddress(ctx,
+		&ttypes.GetTerraformProviderVersionMirrorByAddressInput{
+			GroupPath:         groupPath,
+			RegistryHostname:  parts[len(parts)-4],
+			RegistryNamespace: parts[len(parts)-3],
+			Type:              parts[len(parts)-2],
+			Version:           parts[len(parts)-1],
+		})
+	if err != nil {
+		if tharsis.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(
+				"Import Terraform provider version mirror not found: "+req.ID,
+				"",
+			)
+			return
+		}
+
+		resp.Diagnostics.AddError(
+			"Import Terraform provider version mirror not found: "+req.ID,
+			err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), found.Metadata.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_path"), groupPath)...)
+}
+
+// copyProviderVersionMirror copies the contents of a provider version mirror.
+// It is intended to copy from a struct returned by Tharsis to a Terraform plan or state.
+func (t *terraformProviderVersionMirrorResource) copyProviderVersionMirror(src ttypes.TerraformProviderVersionMirror,
+	dest *TerraformProviderVersionMirrorModel,
+) {
+	// The API doesn't return the group path, so it's kept from the plan or state.
+	dest.ID = types.StringValue(src.Metadata.ID)
+	dest.RegistryHostname = types.StringValue(src.RegistryHostname)
+	dest.RegistryNamespace = types.StringValue(src.RegistryNamespace)
+	dest.Type = types.StringValue(src.Type)
+	dest.SemanticVersion = types.StringValue(src.SemanticVersion)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
+}
+
+// setPlatforms sets the platforms whose packages have been mirrored for a provider version mirror.
+func (t *terraformProviderVersionMirrorResource) setPlatforms(ctx context.Context,
+	mirror *TerraformProviderVersionMirrorModel,
+) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	platformMirrors, err := t.client.TerraformProviderPlatformMirror.GetProviderPlatformMirrorsByVersion(ctx,
+		&ttypes.GetTerraformProviderPlatformMirrorsByVersionInput{
+			VersionMirrorID: mirror.ID.ValueString(),
+		})
+	if err != nil {
+		diags.Append(apiErrorDiagnostic("Error reading mirrored platforms of Terraform provider version", err,
+			mirror.ID.ValueString()))
+		return diags
+	}
+
+	platforms := []string{}
+	for _, platformMirror := range platformMirrors {
+		platforms = append(platforms, platformMirror.OS+"_"+platformMirror.Arch)
+	}
+	sort.Strings(platforms)
+
+	mirror.Platforms, diags = types.ListValueFrom(ctx, types.StringType, platforms)
+	return diags
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestTerraformProviderVersionMirror(t *testing.T) {
+	createVersion := "3.2.1"
+	updateVersion := "3.2.2"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and read back a provider version mirror.
+			{
+				Config: testTerraformProviderVersionMirrorConfiguration(createVersion),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Verify values that should be known.
+					resource.TestCheckResourceAttr("tharsis_terraform_provider_version_mirror.tpvm", "group_path", testGroupPath),
+					resource.TestCheckResourceAttr("tharsis_terraform_provider_version_mirror.tpvm", "registry_hostname", defaultMirrorRegistryHostname),
+					resource.TestCheckResourceAttr("tharsis_terraform_provider_version_mirror.tpvm", "registry_namespace", "hashicorp"),
+					resource.TestCheckResourceAttr("tharsis_terraform_provider_version_mirror.tpvm", "type", "null"),
+					resource.TestCheckResourceAttr("tharsis_terraform_provider_version_mirror.tpvm", "semantic_version", createVersion),
+					// No packages have been mirrored yet.
+					resource.TestCheckResourceAttr("tharsis_terraform_provider_version_mirror.tpvm", "platforms.#", "0"),
+					// Verify dynamic values have any value set in the state.
+					resource.TestCheckResourceAttrSet("tharsis_terraform_provider_version_mirror.tpvm", "id"),
+					resource.TestCheckResourceAttrSet("tharsis_terraform_provider_version_mirror.tpvm", "updated_at"),
+				),
+			},
+			// Import the state.
+			{
+				ResourceName:      "tharsis_terraform_provider_version_mirror.tpvm",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/%s/hashicorp/null/%s", testGroupPath, defaultMirrorRegistryHostname, createVersion),
+				ImportStateVerify: true,
+			},
+			// Update (which requires replacement) and read back.
+			{
+				Config: testTerraformProviderVersionMirrorConfiguration(updateVersion),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_terraform_provider_version_mirror.tpvm", "semantic_version", updateVersion),
+					resource.TestCheckResourceAttrSet("tharsis_terraform_provider_version_mirror.tpvm", "id"),
+					resource.TestCheckResourceAttrSet("tharsis_terraform_provider_version_mirror.tpvm", "updated_at"),
+				),
+			},
+			// Destroy should be covered automatically by TestCase.
+		},
+	})
+}
+
+func testTerraformProviderVersionMirrorConfiguration(version string) string {
+	return fmt.Sprintf(`
+%s
+resource "tharsis_terraform_provider_version_mirror" "tpvm" {
+	group_path = tharsis_group.root-group.full_path
+	registry_namespace = "hashicorp"
+	type = "null"
+	semantic_version = %q
+}
+	`, createRootGroup(testGroupPath, "this is a test root group"), version)
+}
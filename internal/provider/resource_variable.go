@@ -3,13 +3,16 @@ package provider
 import (
 	"context"
 	"errors"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -22,13 +25,17 @@ type VariableModel struct {
 	Category      types.String `tfsdk:"category"`
 	Key           types.String `tfsdk:"key"`
 	Value         types.String `tfsdk:"value"`
+	ValueFromFile types.String `tfsdk:"value_from_file"`
+	ValueJSON     types.String `tfsdk:"value_json"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*variableResource)(nil)
-	_ resource.ResourceWithConfigure   = (*variableResource)(nil)
-	_ resource.ResourceWithImportState = (*variableResource)(nil)
+	_ resource.Resource                   = (*variableResource)(nil)
+	_ resource.ResourceWithConfigure      = (*variableResource)(nil)
+	_ resource.ResourceWithImportState    = (*variableResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*variableResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*variableResource)(nil)
 )
 
 // NewVariableResource is a helper function to simplify the provider implementation.
@@ -38,6 +45,7 @@ func NewVariableResource() resource.Resource {
 
 type variableResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -67,6 +75,9 @@ func (t *variableResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				MarkdownDescription: "The path to this variable's namespace.",
 				Description:         "The path to this variable's namespace.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -75,6 +86,9 @@ func (t *variableResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				MarkdownDescription: "Whether this variable is a Terraform or an environment variable.",
 				Description:         "Whether this variable is a Terraform or an environment variable.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.OneOf("terraform", "environment"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -86,15 +100,95 @@ func (t *variableResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				// Can be updated in place, so no RequiresReplace plan modifier.
 			},
 			"value": schema.StringAttribute{
-				MarkdownDescription: "This variable's value.",
-				Description:         "This variable's value.",
-				Required:            true,
+				MarkdownDescription: "This variable's value. Exactly one of value, value_from_file and value_json must be set.",
+				Description:         "This variable's value. Exactly one of value, value_from_file and value_json must be set.",
+				Optional:            true,
+				Computed:            true, // Set from value_from_file or value_json if they're used instead.
 				// Can be updated in place, so no RequiresReplace plan modifier.
 			},
+			"value_from_file": schema.StringAttribute{
+				MarkdownDescription: "Path of a file to read this variable's value from. The file is read when planning, so changes to it are detected.",
+				Description:         "Path of a file to read this variable's value from. The file is read when planning, so changes to it are detected.",
+				Optional:            true,
+			},
+			"value_json": schema.StringAttribute{
+				MarkdownDescription: "A structured value for this variable, as JSON, such as the output of jsonencode. " +
+					"It is converted to the equivalent HCL.",
+				Description: "A structured value for this variable, as JSON, such as the output of jsonencode. " +
+					"It is converted to the equivalent HCL.",
+				Optional: true,
+			},
 		},
 	}
 }
 
+// ValidateConfig checks that the value is set exactly one way.
+func (t *variableResource) ValidateConfig(ctx context.Context,
+	req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse,
+) {
+	var config VariableModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	count := 0
+	for _, value := range []types.String{config.Value, config.ValueFromFile, config.ValueJSON} {
+		if !value.IsNull() {
+			count++
+		}
+	}
+	if count != 1 {
+		resp.Diagnostics.AddError(
+			"Invalid variable value",
+			"Exactly one of value, value_from_file and value_json must be set.",
+		)
+	}
+}
+
+// ModifyPlan sets the planned value from value_from_file or value_json, so changes to it show up in the plan.
+func (t *variableResource) ModifyPlan(ctx context.Context,
+	req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse,
+) {
+	// Nothing to plan on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan VariableModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch {
+	case plan.ValueFromFile.IsUnknown(), plan.ValueJSON.IsUnknown():
+		plan.Value = types.StringUnknown()
+	case !plan.ValueFromFile.IsNull():
+		contents, err := os.ReadFile(plan.ValueFromFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("value_from_file"),
+				"Error reading variable value",
+				err.Error(),
+			)
+			return
+		}
+		plan.Value = types.StringValue(string(contents))
+	case !plan.ValueJSON.IsNull():
+		value, err := jsonToHCL(plan.ValueJSON.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("value_json"),
+				"Error converting variable value to HCL",
+				err.Error(),
+			)
+			return
+		}
+		plan.Value = types.StringValue(value)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
 // Configure lets the provider implement the ResourceWithConfigure interface.
 func (t *variableResource) Configure(_ context.Context,
 	req resource.ConfigureRequest, _ *resource.ConfigureResponse,
@@ -102,7 +196,9 @@ func (t *variableResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *variableResource) Create(ctx context.Context,
@@ -118,16 +214,13 @@ func (t *variableResource) Create(ctx context.Context,
 	// Create the namespace variable.
 	created, err := t.client.Variable.CreateVariable(ctx,
 		&ttypes.CreateNamespaceVariableInput{
-			NamespacePath: variable.NamespacePath.ValueString(),
+			NamespacePath: t.paths.resolve(variable.NamespacePath.ValueString()),
 			Category:      ttypes.VariableCategory(variable.Category.ValueString()),
 			Key:           variable.Key.ValueString(),
 			Value:         variable.Value.ValueString(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating namespace variable",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating namespace variable", err, variable.NamespacePath.ValueString()))
 		return
 	}
 
@@ -161,14 +254,11 @@ func (t *variableResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "namespace variable", state.ID.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading namespace variable",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading namespace variable", err, state.NamespacePath.ValueString()))
 		return
 	}
 
@@ -205,10 +295,7 @@ func (t *variableResource) Update(ctx context.Context,
 			Value: plan.Value.ValueString(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating namespace variable",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating namespace variable", err, plan.NamespacePath.ValueString()))
 		return
 	}
 
@@ -248,10 +335,7 @@ func (t *variableResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting namespace variable",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting namespace variable", err, state.NamespacePath.ValueString()))
 	}
 }
 
@@ -259,8 +343,14 @@ func (t *variableResource) Delete(ctx context.Context,
 func (t *variableResource) ImportState(ctx context.Context,
 	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
 ) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Get the namespace variable by ID or resource path from Tharsis.
+	importByResourcePath(ctx, req, resp, "variable", "namespace variable", func(id string) (string, error) {
+		found, err := t.client.Variable.GetVariable(ctx, &ttypes.GetNamespaceVariableInput{ID: id})
+		if err != nil {
+			return "", err
+		}
+		return found.Metadata.ID, nil
+	})
 }
 
 // copyVariable copies the contents of a namespace variable.
@@ -271,7 +361,7 @@ func (t *variableResource) copyVariable(src ttypes.NamespaceVariable, dest *Vari
 	}
 
 	dest.ID = types.StringValue(src.Metadata.ID)
-	dest.NamespacePath = types.StringValue(src.NamespacePath)
+	dest.NamespacePath = t.paths.keep(dest.NamespacePath, src.NamespacePath)
 	dest.Category = types.StringValue(string(src.Category))
 	dest.Key = types.StringValue(src.Key)
 	dest.Value = types.StringValue(*src.Value)
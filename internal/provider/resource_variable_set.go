@@ -0,0 +1,389 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
+	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// VariableSetModel is the model for a set of namespace variables.
+// VariableIDs maps each variable's category and key, such as "terraform/region", to its ID.
+type VariableSetModel struct {
+	ID                   types.String `tfsdk:"id"`
+	NamespacePath        types.String `tfsdk:"namespace_path"`
+	TerraformVariables   types.Map    `tfsdk:"terraform_variables"`
+	EnvironmentVariables types.Map    `tfsdk:"environment_variables"`
+	VariableIDs          types.Map    `tfsdk:"variable_ids"`
+}
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ resource.Resource              = (*variableSetResource)(nil)
+	_ resource.ResourceWithConfigure = (*variableSetResource)(nil)
+)
+
+// NewVariableSetResource is a helper function to simplify the provider implementation.
+func NewVariableSetResource() resource.Resource {
+	return &variableSetResource{}
+}
+
+type variableSetResource struct {
+	client *tharsis.Client
+	paths  namespacePaths
+}
+
+// Metadata returns the full name of the resource, including prefix, underscore, instance name.
+func (t *variableSetResource) Metadata(_ context.Context,
+	_ resource.MetadataRequest, resp *resource.MetadataResponse,
+) {
+	resp.TypeName = "tharsis_variable_set"
+}
+
+func (t *variableSetResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	description := "Defines and manages a set of Terraform and environment variables in one namespace. " +
+		"Only the variables in the set are managed, so other variables in the namespace are left alone."
+
+	resp.Schema = schema.Schema{
+		Version:             1,
+		MarkdownDescription: description,
+		Description:         description,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the variable set, which is the namespace path.",
+				Description:         "String identifier of the variable set, which is the namespace path.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"namespace_path": schema.StringAttribute{
+				MarkdownDescription: "The path to the namespace of the variables.",
+				Description:         "The path to the namespace of the variables.",
+				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"terraform_variables": schema.MapAttribute{
+				MarkdownDescription: "Terraform variables, by key.",
+				Description:         "Terraform variables, by key.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"environment_variables": schema.MapAttribute{
+				MarkdownDescription: "Environment variables, by key.",
+				Description:         "Environment variables, by key.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"variable_ids": schema.MapAttribute{
+				MarkdownDescription: "String identifiers of the variables, by category and key, such as terraform/region.",
+				Description:         "String identifiers of the variables, by category and key, such as terraform/region.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure lets the provider implement the ResourceWithConfigure interface.
+func (t *variableSetResource) Configure(_ context.Context,
+	req resource.ConfigureRequest, _ *resource.ConfigureResponse,
+) {
+	if req.ProviderData == nil {
+		return
+	}
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
+}
+
+func (t *variableSetResource) Create(ctx context.Context,
+	req resource.CreateRequest, resp *resource.CreateResponse,
+) {
+	// Retrieve values from plan.
+	var plan VariableSetModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	want, diags := t.getVariables(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create the variables.
+	ids, err := t.reconcileVariables(ctx, t.paths.resolve(plan.NamespacePath.ValueString()),
+		map[string]string{}, map[string]string{}, want)
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating variable set", err, plan.NamespacePath.ValueString()))
+	}
+
+	// Set the response state to the plan with whichever variables were created, whether or not there is an error.
+	plan.ID = plan.NamespacePath
+	resp.Diagnostics.Append(t.setVariableIDs(ctx, ids, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (t *variableSetResource) Read(ctx context.Context,
+	req resource.ReadRequest, resp *resource.ReadResponse,
+) {
+	// Get the current state.
+	var state VariableSetModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	haveIDs := map[string]string{}
+	resp.Diagnostics.Append(state.VariableIDs.ElementsAs(ctx, &haveIDs, false)...)
+	haveValues, diags := t.getVariables(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get each variable from Tharsis.  Variables that were deleted outside Terraform drop out of the set.
+	ids := map[string]string{}
+	values := map[string]string{}
+	for _, id := range haveIDs {
+		found, err := t.client.Variable.GetVariable(ctx, &ttypes.GetNamespaceVariableInput{
+			ID: id,
+		})
+		if err != nil {
+			if tharsis.IsNotFoundError(err) {
+				continue
+			}
+
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error reading variable set", err, state.NamespacePath.ValueString()))
+			return
+		}
+
+		name := variableSetName(string(found.Category), found.Key)
+		ids[name] = found.Metadata.ID
+		if found.Value != nil {
+			values[name] = *found.Value
+		} else {
+			// The value isn't returned without permission to view it.
+			values[name] = haveValues[name]
+		}
+	}
+
+	resp.Diagnostics.Append(t.setVariables(ctx, values, &state)...)
+	resp.Diagnostics.Append(t.setVariableIDs(ctx, ids, &state)...)
+
+	// Set the refreshed state, whether or not there is an error.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (t *variableSetResource) Update(ctx context.Context,
+	req resource.UpdateRequest, resp *resource.UpdateResponse,
+) {
+	// Retrieve values from plan and state.
+	var plan, state VariableSetModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	haveIDs := map[string]string{}
+	resp.Diagnostics.Append(state.VariableIDs.ElementsAs(ctx, &haveIDs, false)...)
+	haveValues, diags := t.getVariables(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	want, diags := t.getVariables(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create, update and delete variables so the namespace matches the plan.
+	ids, err := t.reconcileVariables(ctx, t.paths.resolve(plan.NamespacePath.ValueString()), haveIDs, haveValues, want)
+	if err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating variable set", err, plan.NamespacePath.ValueString()))
+	}
+
+	// Set the response state to the plan with whichever variables now exist, with or without error.
+	resp.Diagnostics.Append(t.setVariableIDs(ctx, ids, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (t *variableSetResource) Delete(ctx context.Context,
+	req resource.DeleteRequest, resp *resource.DeleteResponse,
+) {
+	// Get the current state.
+	var state VariableSetModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	haveIDs := map[string]string{}
+	resp.Diagnostics.Append(state.VariableIDs.ElementsAs(ctx, &haveIDs, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete all the variables via Tharsis.
+	if _, err := t.reconcileVariables(ctx, t.paths.resolve(state.NamespacePath.ValueString()),
+		haveIDs, map[string]string{}, map[string]string{}); err != nil {
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting variable set", err, state.NamespacePath.ValueString()))
+	}
+}
+
+// reconcileVariables deletes, updates and creates variables in a namespace so they match want.
+// The variables are named by category and key, and have and want map those names to IDs and values.
+// It returns the IDs of the variables that exist afterward, even if there is an error.
+func (t *variableSetResource) reconcileVariables(ctx context.Context, namespacePath string,
+	haveIDs, haveValues, want map[string]string,
+) (map[string]string, error) {
+	ids := map[string]string{}
+	for name, id := range haveIDs {
+		ids[name] = id
+	}
+
+	// Delete variables first, so a variable can move between the maps without a conflict.
+	for _, name := range sortedKeys(haveIDs) {
+		if _, ok := want[name]; ok {
+			continue
+		}
+
+		err := t.client.Variable.DeleteVariable(ctx, &ttypes.DeleteNamespaceVariableInput{
+			ID: haveIDs[name],
+		})
+		if err != nil && !tharsis.IsNotFoundError(err) {
+			return ids, fmt.Errorf("failed to delete variable %s: %w", name, err)
+		}
+		delete(ids, name)
+	}
+
+	for _, name := range sortedKeys(want) {
+		category, key, _ := strings.Cut(name, "/")
+		value := want[name]
+
+		if id, ok := haveIDs[name]; ok {
+			if value == haveValues[name] {
+				continue
+			}
+
+			if _, err := t.client.Variable.UpdateVariable(ctx, &ttypes.UpdateNamespaceVariableInput{
+				ID:    id,
+				Key:   key,
+				Value: value,
+			}); err != nil {
+				return ids, fmt.Errorf("failed to update variable %s: %w", name, err)
+			}
+			continue
+		}
+
+		created, err := t.client.Variable.CreateVariable(ctx, &ttypes.CreateNamespaceVariableInput{
+			NamespacePath: namespacePath,
+			Category:      ttypes.VariableCategory(category),
+			Key:           key,
+			Value:         value,
+		})
+		if err != nil {
+			return ids, fmt.Errorf("failed to create variable %s: %w", name, err)
+		}
+		ids[name] = created.Metadata.ID
+	}
+
+	return ids, nil
+}
+
+// getVariables returns the values of the variables in a plan or state, named by category and key.
+func (t *variableSetResource) getVariables(ctx context.Context, model VariableSetModel) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	result := map[string]string{}
+
+	for category, variables := range map[string]types.Map{
+		string(ttypes.TerraformVariableCategory):   model.TerraformVariables,
+		string(ttypes.EnvironmentVariableCategory): model.EnvironmentVariables,
+	} {
+		values := map[string]string{}
+		diags.Append(variables.ElementsAs(ctx, &values, false)...)
+		for key, value := range values {
+			result[variableSetName(category, key)] = value
+		}
+	}
+
+	return result, diags
+}
+
+// setVariables copies the values of variables named by category and key into a state.
+// A map with no variables is kept as null, unless it was empty.
+func (t *variableSetResource) setVariables(ctx context.Context, values map[string]string, dest *VariableSetModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	terraformVariables := map[string]string{}
+	environmentVariables := map[string]string{}
+	for name, value := range values {
+		category, key, _ := strings.Cut(name, "/")
+		switch ttypes.VariableCategory(category) {
+		case ttypes.TerraformVariableCategory:
+			terraformVariables[key] = value
+		case ttypes.EnvironmentVariableCategory:
+			environmentVariables[key] = value
+		}
+	}
+
+	for _, item := range []struct {
+		values map[string]string
+		dest   *types.Map
+	}{
+		{values: terraformVariables, dest: &dest.TerraformVariables},
+		{values: environmentVariables, dest: &dest.EnvironmentVariables},
+	} {
+		if len(item.values) == 0 && item.dest.IsNull() {
+			continue
+		}
+
+		value, valueDiags := types.MapValueFrom(ctx, types.StringType, item.values)
+		diags.Append(valueDiags...)
+		*item.dest = value
+	}
+
+	return diags
+}
+
+// setVariableIDs copies the IDs of the variables into a plan or state.
+func (t *variableSetResource) setVariableIDs(ctx context.Context, ids map[string]string, dest *VariableSetModel) diag.Diagnostics {
+	value, diags := types.MapValueFrom(ctx, types.StringType, ids)
+	dest.VariableIDs = value
+	return diags
+}
+
+// variableSetName returns the name of a variable in a variable set, which is its category and key.
+func variableSetName(category, key string) string {
+	return category + "/" + key
+}
+
+// sortedKeys returns the keys of a map in order, so variables are always changed in the same order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestVariableSet(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and read back a variable set.
+			{
+				Config: testVariableSetConfiguration(`
+	terraform_variables = {
+		region = "us-east-1"
+		size   = "small"
+	}
+	environment_variables = {
+		TF_LOG = "INFO"
+	}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "namespace_path", testGroupPath),
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "terraform_variables.%", "2"),
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "terraform_variables.region", "us-east-1"),
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "environment_variables.TF_LOG", "INFO"),
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "variable_ids.%", "3"),
+					resource.TestCheckResourceAttrSet("tharsis_variable_set.tvs", "variable_ids.terraform/region"),
+					resource.TestCheckResourceAttrSet("tharsis_variable_set.tvs", "variable_ids.environment/TF_LOG"),
+				),
+			},
+
+			// Update one variable, remove one, and add one.
+			{
+				Config: testVariableSetConfiguration(`
+	terraform_variables = {
+		region = "us-west-2"
+		count  = "3"
+	}`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "terraform_variables.%", "2"),
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "terraform_variables.region", "us-west-2"),
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "terraform_variables.count", "3"),
+					resource.TestCheckNoResourceAttr("tharsis_variable_set.tvs", "environment_variables.%"),
+					resource.TestCheckResourceAttr("tharsis_variable_set.tvs", "variable_ids.%", "2"),
+				),
+			},
+
+			// Destroy should be covered automatically by TestCase.
+
+		},
+	})
+}
+
+func testVariableSetConfiguration(variables string) string {
+	return fmt.Sprintf(`
+
+%s
+
+resource "tharsis_variable_set" "tvs" {
+	namespace_path = tharsis_group.root-group.full_path%s
+}
+	`, createRootGroup(testGroupPath, "this is a test root group"), variables)
+}
@@ -40,6 +40,14 @@ func TestVariable(t *testing.T) {
 				ImportStateVerify: true,
 			},
 
+			// Import the state by resource path.
+			{
+				ResourceName:      "tharsis_variable.tnv",
+				ImportStateId:     createNamespacePath + "/" + createCategory + "/" + createKey,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+
 			// Update and read.
 			{
 				Config: testVariableConfigurationUpdate(),
@@ -61,6 +69,34 @@ func TestVariable(t *testing.T) {
 	})
 }
 
+func TestVariableValueJSON(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and read back a variable with a structured value.
+			{
+				Config: fmt.Sprintf(`
+
+%s
+
+resource "tharsis_variable" "tjv" {
+	namespace_path = tharsis_group.root-group.full_path
+	category = "terraform"
+	key = "json-key"
+	value_json = jsonencode({ name = "web", ports = [80] })
+}
+	`, createRootGroup(testGroupPath, "this is a test root group")),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("tharsis_variable.tjv", "value", "{\n  name = \"web\"\n  ports = [\n    80,\n  ]\n}"),
+				),
+			},
+
+			// Destroy should be covered automatically by TestCase.
+
+		},
+	})
+}
+
 func testVariableConfigurationCreate() string {
 	createCategory := "terraform"
 	createKey := "first-key"
@@ -2,42 +2,47 @@ package provider
 
 import (
 	"context"
-	"time"
 
 	"github.com/aws/smithy-go/ptr"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
 
 // VCSProviderModel is the model for a VCS provider.
 type VCSProviderModel struct {
-	ResourcePath          types.String `tfsdk:"resource_path"`
-	LastUpdated           types.String `tfsdk:"last_updated"`
-	CreatedBy             types.String `tfsdk:"created_by"`
-	Name                  types.String `tfsdk:"name"`
-	Description           types.String `tfsdk:"description"`
-	GroupPath             types.String `tfsdk:"group_path"`
-	ID                    types.String `tfsdk:"id"`
-	URL                   types.String `tfsdk:"url"`
-	Type                  types.String `tfsdk:"type"`
-	OAuthClientID         types.String `tfsdk:"oauth_client_id"`
-	OAuthClientSecret     types.String `tfsdk:"oauth_client_secret"`
-	OAuthAuthorizationURL types.String `tfsdk:"oauth_authorization_url"`
-	AutoCreateWebhooks    types.Bool   `tfsdk:"auto_create_webhooks"`
+	ResourcePath             types.String `tfsdk:"resource_path"`
+	LastUpdated              types.String `tfsdk:"last_updated"`
+	CreatedBy                types.String `tfsdk:"created_by"`
+	CreatedAt                types.String `tfsdk:"created_at"`
+	UpdatedAt                types.String `tfsdk:"updated_at"`
+	MetadataVersion          types.String `tfsdk:"metadata_version"`
+	Name                     types.String `tfsdk:"name"`
+	Description              types.String `tfsdk:"description"`
+	GroupPath                types.String `tfsdk:"group_path"`
+	ID                       types.String `tfsdk:"id"`
+	URL                      types.String `tfsdk:"url"`
+	Type                     types.String `tfsdk:"type"`
+	OAuthClientID            types.String `tfsdk:"oauth_client_id"`
+	OAuthClientSecret        types.String `tfsdk:"oauth_client_secret"`
+	OAuthClientSecretVersion types.String `tfsdk:"oauth_client_secret_version"`
+	OAuthAuthorizationURL    types.String `tfsdk:"oauth_authorization_url"`
+	AutoCreateWebhooks       types.Bool   `tfsdk:"auto_create_webhooks"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*vcsProviderResource)(nil)
-	_ resource.ResourceWithConfigure   = (*vcsProviderResource)(nil)
-	_ resource.ResourceWithImportState = (*vcsProviderResource)(nil)
+	_ resource.Resource                 = (*vcsProviderResource)(nil)
+	_ resource.ResourceWithConfigure    = (*vcsProviderResource)(nil)
+	_ resource.ResourceWithImportState  = (*vcsProviderResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*vcsProviderResource)(nil)
 )
 
 // NewVCSProviderResource is a helper function to simplify the provider implementation.
@@ -47,6 +52,7 @@ func NewVCSProviderResource() resource.Resource {
 
 type vcsProviderResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -60,7 +66,7 @@ func (t *vcsProviderResource) Schema(_ context.Context, _ resource.SchemaRequest
 	description := "Defines and manages a VCS provider."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -72,14 +78,6 @@ func (t *vcsProviderResource) Schema(_ context.Context, _ resource.SchemaRequest
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
-			"created_by": schema.StringAttribute{
-				MarkdownDescription: "The email address of the user or account that created this VCS provider.",
-				Description:         "The email address of the user or account that created this VCS provider.",
-				Computed:            true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.UseStateForUnknown(),
-				},
-			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "The name of the VCS provider.",
 				Description:         "The name of the VCS provider.",
@@ -98,6 +96,9 @@ func (t *vcsProviderResource) Schema(_ context.Context, _ resource.SchemaRequest
 				MarkdownDescription: "The path of the group where this VCS provider resides.",
 				Description:         "The path of the group where this VCS provider resides.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -123,6 +124,9 @@ func (t *vcsProviderResource) Schema(_ context.Context, _ resource.SchemaRequest
 				MarkdownDescription: "The type of this VCS provider: gitlab, github, etc.",
 				Description:         "The type of this VCS provider: gitlab, github, etc.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.OneOf("gitlab", "github"),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -136,19 +140,28 @@ func (t *vcsProviderResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 			"oauth_client_id": schema.StringAttribute{
-				MarkdownDescription: "A description of the VCS provider.",
-				Description:         "A description of the VCS provider.",
+				MarkdownDescription: "Client ID of the OAuth application for this VCS provider.",
+				Description:         "Client ID of the OAuth application for this VCS provider.",
 				Required:            true,
 				// Can be updated in place, so no RequiresReplace plan modifier.
 				// Is write-only, so will not be set after import.
 			},
 			"oauth_client_secret": schema.StringAttribute{
-				MarkdownDescription: "A description of the VCS provider.",
-				Description:         "A description of the VCS provider.",
+				MarkdownDescription: "Client secret of the OAuth application for this VCS provider.",
+				Description:         "Client secret of the OAuth application for this VCS provider.",
 				Required:            true,
+				Sensitive:           true,
 				// Can be updated in place, so no RequiresReplace plan modifier.
 				// Is write-only, so will not be set after import.
 			},
+			"oauth_client_secret_version": schema.StringAttribute{
+				MarkdownDescription: "An arbitrary version of the OAuth client secret. Tharsis never returns the secret, " +
+					"so change this to send the secret again, such as after it was rotated outside Terraform.",
+				Description: "An arbitrary version of the OAuth client secret. Tharsis never returns the secret, " +
+					"so change this to send the secret again, such as after it was rotated outside Terraform.",
+				Optional: true,
+				// Can be updated in place, so no RequiresReplace plan modifier.
+			},
 			"oauth_authorization_url": schema.StringAttribute{
 				MarkdownDescription: "URL to use to complete OAuth flow for any links to this VCS provider.",
 				Description:         "URL to use to complete OAuth flow for any links to this VCS provider.",
@@ -159,9 +172,20 @@ func (t *vcsProviderResource) Schema(_ context.Context, _ resource.SchemaRequest
 				MarkdownDescription: "Timestamp when this VCS provider was most recently updated.",
 				Description:         "Timestamp when this VCS provider was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
 			},
 		},
 	}
+
+	resp.Schema.Attributes["created_by"] = createdByAttribute("VCS provider")
+	for name, attribute := range metadataAttributes("VCS provider") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *vcsProviderResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_vcs_provider", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -171,7 +195,9 @@ func (t *vcsProviderResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *vcsProviderResource) Create(ctx context.Context,
@@ -189,7 +215,7 @@ func (t *vcsProviderResource) Create(ctx context.Context,
 		&ttypes.CreateVCSProviderInput{
 			Name:               vcsProvider.Name.ValueString(),
 			Description:        vcsProvider.Description.ValueString(),
-			GroupPath:          vcsProvider.GroupPath.ValueString(),
+			GroupPath:          t.paths.resolve(vcsProvider.GroupPath.ValueString()),
 			URL:                ptr.String(vcsProvider.URL.ValueString()),
 			Type:               ttypes.VCSProviderType(vcsProvider.Type.ValueString()),
 			AutoCreateWebhooks: vcsProvider.AutoCreateWebhooks.ValueBool(),
@@ -197,10 +223,7 @@ func (t *vcsProviderResource) Create(ctx context.Context,
 			OAuthClientSecret:  vcsProvider.OAuthClientSecret.ValueString(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating VCS provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating VCS provider", err, vcsProvider.GroupPath.ValueString()))
 		return
 	}
 
@@ -228,14 +251,11 @@ func (t *vcsProviderResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "VCS provider", state.ResourcePath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading VCS provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading VCS provider", err, state.ResourcePath.ValueString()))
 		return
 	}
 
@@ -249,13 +269,20 @@ func (t *vcsProviderResource) Read(ctx context.Context,
 func (t *vcsProviderResource) Update(ctx context.Context,
 	req resource.UpdateRequest, resp *resource.UpdateResponse,
 ) {
-	// Retrieve values from plan.
-	var plan VCSProviderModel
+	// Retrieve values from plan and state.
+	var plan, state VCSProviderModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// The client secret is only sent when it or its version changed.
+	var oauthClientSecret *string
+	if !plan.OAuthClientSecret.Equal(state.OAuthClientSecret) || !plan.OAuthClientSecretVersion.Equal(state.OAuthClientSecretVersion) {
+		oauthClientSecret = ptr.String(plan.OAuthClientSecret.ValueString())
+	}
+
 	// Update the VCS provider via Tharsis.
 	// The ID is used to find the record to update.
 	updated, err := t.client.VCSProvider.UpdateProvider(ctx,
@@ -263,13 +290,10 @@ func (t *vcsProviderResource) Update(ctx context.Context,
 			ID:                plan.ID.ValueString(),
 			Description:       ptr.String(plan.Description.ValueString()),
 			OAuthClientID:     ptr.String(plan.OAuthClientID.ValueString()),
-			OAuthClientSecret: ptr.String(plan.OAuthClientSecret.ValueString()),
+			OAuthClientSecret: oauthClientSecret,
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating VCS provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating VCS provider", err, state.ResourcePath.ValueString()))
 		return
 	}
 
@@ -303,10 +327,7 @@ func (t *vcsProviderResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting VCS provider",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting VCS provider", err, state.ResourcePath.ValueString()))
 	}
 }
 
@@ -314,8 +335,14 @@ func (t *vcsProviderResource) Delete(ctx context.Context,
 func (t *vcsProviderResource) ImportState(ctx context.Context,
 	req resource.ImportStateRequest, resp *resource.ImportStateResponse,
 ) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	// Get the VCS provider by ID or resource path from Tharsis.
+	importByResourcePath(ctx, req, resp, "vcs_provider", "VCS provider", func(id string) (string, error) {
+		found, err := t.client.VCSProvider.GetProvider(ctx, &ttypes.GetVCSProviderInput{ID: id})
+		if err != nil {
+			return "", err
+		}
+		return found.Metadata.ID, nil
+	})
 }
 
 // copyVCSProvider copies the contents of a VCS provider.
@@ -323,10 +350,9 @@ func (t *vcsProviderResource) ImportState(ctx context.Context,
 func (t *vcsProviderResource) copyVCSProvider(src ttypes.VCSProvider, dest *VCSProviderModel) {
 	dest.ID = types.StringValue(src.Metadata.ID)
 	dest.Name = types.StringValue(src.Name)
-	dest.CreatedBy = types.StringValue(src.CreatedBy)
 	dest.Description = types.StringValue(src.Description)
 	dest.URL = types.StringValue(src.URL)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 	dest.ResourcePath = types.StringValue(src.ResourcePath)
 	dest.Type = types.StringValue(string(src.Type))
 	dest.AutoCreateWebhooks = types.BoolValue(src.AutoCreateWebhooks)
@@ -336,5 +362,7 @@ func (t *vcsProviderResource) copyVCSProvider(src ttypes.VCSProvider, dest *VCSP
 	dest.OAuthAuthorizationURL = types.StringValue("")
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	dest.CreatedBy = types.StringValue(src.CreatedBy)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
 }
@@ -2,7 +2,7 @@ package provider
 
 import (
 	"context"
-	"time"
+	"fmt"
 
 	"github.com/aws/smithy-go/ptr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -10,15 +10,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
 
 // WorkspaceModel is the model for a workspace.
-// Fields intentionally omitted: AssignedManagedIdentities, ManagedIdentities, ServiceAccounts,
+// Fields intentionally omitted: ManagedIdentities, ServiceAccounts,
 // StateVersions, Memberships, Variables, ActivityEvents.
-// Also for now, omitting DirtyState, Locked, CurrentStateVersionID, and CurrentJobID.
 type WorkspaceModel struct {
 	ID                 types.String `tfsdk:"id"`
 	Name               types.String `tfsdk:"name"`
@@ -27,15 +28,23 @@ type WorkspaceModel struct {
 	GroupPath          types.String `tfsdk:"group_path"`
 	TerraformVersion   types.String `tfsdk:"terraform_version"`
 	LastUpdated        types.String `tfsdk:"last_updated"`
+	CreatedAt          types.String `tfsdk:"created_at"`
+	UpdatedAt          types.String `tfsdk:"updated_at"`
+	MetadataVersion    types.String `tfsdk:"metadata_version"`
 	MaxJobDuration     types.Int64  `tfsdk:"max_job_duration"`
 	PreventDestroyPlan types.Bool   `tfsdk:"prevent_destroy_plan"`
+	// Read-only current state version of the workspace, for health checks and gating.
+	CurrentStateVersionID types.String `tfsdk:"current_state_version_id"`
+	// AssignedManagedIdentities is only managed if set, so it can coexist with tharsis_assigned_managed_identity.
+	AssignedManagedIdentities types.Set `tfsdk:"assigned_managed_identities"`
 }
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*workspaceResource)(nil)
-	_ resource.ResourceWithConfigure   = (*workspaceResource)(nil)
-	_ resource.ResourceWithImportState = (*workspaceResource)(nil)
+	_ resource.Resource                 = (*workspaceResource)(nil)
+	_ resource.ResourceWithConfigure    = (*workspaceResource)(nil)
+	_ resource.ResourceWithImportState  = (*workspaceResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*workspaceResource)(nil)
 )
 
 // NewWorkspaceResource is a helper function to simplify the provider implementation.
@@ -44,7 +53,9 @@ func NewWorkspaceResource() resource.Resource {
 }
 
 type workspaceResource struct {
-	client *tharsis.Client
+	client     *tharsis.Client
+	paths      namespacePaths
+	workspaces *workspaceCache
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -58,7 +69,7 @@ func (t *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	description := "Defines and manages a workspace."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -96,6 +107,9 @@ func (t *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				MarkdownDescription: "Path of the parent group.",
 				Description:         "Path of the parent group.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -125,9 +139,33 @@ func (t *workspaceResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				MarkdownDescription: "Timestamp when this workspace was most recently updated.",
 				Description:         "Timestamp when this workspace was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
+			},
+			"assigned_managed_identities": schema.SetAttribute{
+				MarkdownDescription: "IDs of the managed identities assigned to this workspace. If set, assignments not in the set are removed, " +
+					"so don't combine it with tharsis_assigned_managed_identity resources for the same workspace.",
+				Description: "IDs of the managed identities assigned to this workspace. If set, assignments not in the set are removed, " +
+					"so don't combine it with tharsis_assigned_managed_identity resources for the same workspace.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			// The current state version is read-only, and changes as runs happen.
+			"current_state_version_id": schema.StringAttribute{
+				MarkdownDescription: "String identifier of the workspace's current state version, if any.",
+				Description:         "String identifier of the workspace's current state version, if any.",
+				Computed:            true,
 			},
 		},
 	}
+
+	for name, attribute := range metadataAttributes("workspace") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *workspaceResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_workspace", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -137,7 +175,10 @@ func (t *workspaceResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
+	t.workspaces = p.workspaces
 }
 
 func (t *workspaceResource) Create(ctx context.Context,
@@ -167,16 +208,13 @@ func (t *workspaceResource) Create(ctx context.Context,
 		&ttypes.CreateWorkspaceInput{
 			Name:               workspace.Name.ValueString(),
 			Description:        workspace.Description.ValueString(),
-			GroupPath:          workspace.GroupPath.ValueString(),
+			GroupPath:          t.paths.resolve(workspace.GroupPath.ValueString()),
 			MaxJobDuration:     maxJobDuration,
 			TerraformVersion:   terraformVersion,
 			PreventDestroyPlan: preventDestroyPlan,
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating workspace",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating workspace", err, workspace.GroupPath.ValueString()))
 		return
 	}
 
@@ -184,6 +222,17 @@ func (t *workspaceResource) Create(ctx context.Context,
 	// Because the schema uses the Set type rather than the List type, make sure to set all fields.
 	t.copyWorkspace(*created, &workspace)
 
+	// Assign the managed identities, if any.
+	if !workspace.AssignedManagedIdentities.IsNull() {
+		var wantIDs []string
+		resp.Diagnostics.Append(workspace.AssignedManagedIdentities.ElementsAs(ctx, &wantIDs, false)...)
+		if !resp.Diagnostics.HasError() {
+			if err = t.updateAssignedManagedIdentities(ctx, created.FullPath, nil, wantIDs); err != nil {
+				resp.Diagnostics.Append(apiErrorDiagnostic("Error assigning managed identities to workspace", err, created.FullPath))
+			}
+		}
+	}
+
 	// Set the response state to the fully-populated plan, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, workspace)...)
 }
@@ -198,26 +247,36 @@ func (t *workspaceResource) Read(ctx context.Context,
 		return
 	}
 
-	// Get the workspace from Tharsis.
-	found, err := t.client.Workspaces.GetWorkspace(ctx, &ttypes.GetWorkspaceInput{
+	// Get the workspace from Tharsis, or from another resource that read it in this refresh.
+	found, err := t.workspaces.GetWorkspace(ctx, &ttypes.GetWorkspaceInput{
 		ID: ptr.String(state.ID.ValueString()),
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "workspace", state.FullPath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading workspace",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading workspace", err, state.FullPath.ValueString()))
 		return
 	}
 
 	// Copy the from-Tharsis struct to the state.
 	t.copyWorkspace(*found, &state)
 
+	// Reconcile the assigned managed identities, but only if they're managed here.
+	if !state.AssignedManagedIdentities.IsNull() {
+		assignedIDs, err := t.getAssignedManagedIdentityIDs(ctx, found.Metadata.ID)
+		if err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error reading assigned managed identities", err, state.FullPath.ValueString()))
+			return
+		}
+
+		assigned, diags := types.SetValueFrom(ctx, types.StringType, assignedIDs)
+		resp.Diagnostics.Append(diags...)
+		state.AssignedManagedIdentities = assigned
+	}
+
 	// Set the refreshed state, whether or not there is an error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -231,6 +290,7 @@ func (t *workspaceResource) Update(ctx context.Context,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	t.workspaces.forget(&ttypes.GetWorkspaceInput{ID: ptr.String(plan.ID.ValueString())})
 
 	// Update the workspace via Tharsis.
 	// The ID is used to find the record to update.
@@ -256,16 +316,43 @@ func (t *workspaceResource) Update(ctx context.Context,
 			PreventDestroyPlan: preventDestroyPlan,
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating workspace",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating workspace", err, plan.FullPath.ValueString()))
 		return
 	}
 
 	// Copy all fields returned by Tharsis back into the plan.
 	t.copyWorkspace(*updated, &plan)
 
+	// Add and remove assignments of managed identities incrementally.
+	// If they're no longer managed here, existing assignments are left alone.
+	if !plan.AssignedManagedIdentities.IsNull() {
+		var state WorkspaceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		var haveIDs, wantIDs []string
+		if state.AssignedManagedIdentities.IsNull() {
+			// Start managing whatever is currently assigned.
+			haveIDs, err = t.getAssignedManagedIdentityIDs(ctx, updated.Metadata.ID)
+			if err != nil {
+				resp.Diagnostics.Append(apiErrorDiagnostic("Error reading assigned managed identities", err, state.FullPath.ValueString()))
+				return
+			}
+		} else {
+			resp.Diagnostics.Append(state.AssignedManagedIdentities.ElementsAs(ctx, &haveIDs, false)...)
+		}
+		resp.Diagnostics.Append(plan.AssignedManagedIdentities.ElementsAs(ctx, &wantIDs, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if err = t.updateAssignedManagedIdentities(ctx, updated.FullPath, haveIDs, wantIDs); err != nil {
+			resp.Diagnostics.Append(apiErrorDiagnostic("Error updating assigned managed identities", err, state.FullPath.ValueString()))
+		}
+	}
+
 	// Set the response state to the fully-populated plan, with or without error.
 	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
@@ -279,6 +366,7 @@ func (t *workspaceResource) Delete(ctx context.Context,
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	t.workspaces.forget(&ttypes.GetWorkspaceInput{ID: ptr.String(state.ID.ValueString())})
 
 	// Delete the workspace via Tharsis.
 	err := t.client.Workspaces.DeleteWorkspace(ctx,
@@ -293,10 +381,7 @@ func (t *workspaceResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting workspace",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting workspace", err, state.FullPath.ValueString()))
 	}
 }
 
@@ -328,6 +413,67 @@ func (t *workspaceResource) ImportState(ctx context.Context,
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), found.Metadata.ID)...)
 }
 
+// getAssignedManagedIdentityIDs returns the IDs of the managed identities assigned to a workspace.
+func (t *workspaceResource) getAssignedManagedIdentityIDs(ctx context.Context, workspaceID string) ([]string, error) {
+	managedIdentities, err := t.client.Workspaces.GetAssignedManagedIdentities(ctx,
+		&ttypes.GetAssignedManagedIdentitiesInput{
+			ID: &workspaceID,
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []string{}
+	for _, managedIdentity := range managedIdentities {
+		result = append(result, managedIdentity.Metadata.ID)
+	}
+
+	return result, nil
+}
+
+// updateAssignedManagedIdentities assigns the wanted managed identities that aren't yet assigned to
+// a workspace, and unassigns those that are no longer wanted.
+func (t *workspaceResource) updateAssignedManagedIdentities(ctx context.Context,
+	workspacePath string, haveIDs, wantIDs []string,
+) error {
+	have := map[string]bool{}
+	for _, id := range haveIDs {
+		have[id] = true
+	}
+	want := map[string]bool{}
+	for _, id := range wantIDs {
+		want[id] = true
+	}
+
+	for _, id := range wantIDs {
+		if have[id] {
+			continue
+		}
+		if _, err := t.client.ManagedIdentity.AssignManagedIdentityToWorkspace(ctx,
+			&ttypes.AssignManagedIdentityInput{
+				ManagedIdentityID: ptr.String(id),
+				WorkspacePath:     workspacePath,
+			}); err != nil {
+			return fmt.Errorf("failed to assign managed identity %s: %v", id, err)
+		}
+	}
+
+	for _, id := range haveIDs {
+		if want[id] {
+			continue
+		}
+		if _, err := t.client.ManagedIdentity.UnassignManagedIdentityFromWorkspace(ctx,
+			&ttypes.AssignManagedIdentityInput{
+				ManagedIdentityID: ptr.String(id),
+				WorkspacePath:     workspacePath,
+			}); err != nil && !tharsis.IsNotFoundError(err) {
+			return fmt.Errorf("failed to unassign managed identity %s: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
 // copyWorkspace copies the contents of a workspace.
 // It is intended to copy from a struct returned by Tharsis to a Terraform plan or state.
 func (t *workspaceResource) copyWorkspace(src ttypes.Workspace, dest *WorkspaceModel) {
@@ -335,11 +481,17 @@ func (t *workspaceResource) copyWorkspace(src ttypes.Workspace, dest *WorkspaceM
 	dest.Name = types.StringValue(src.Name)
 	dest.Description = types.StringValue(src.Description)
 	dest.FullPath = types.StringValue(src.FullPath)
-	dest.GroupPath = types.StringValue(src.GroupPath)
+	dest.GroupPath = t.paths.keep(dest.GroupPath, src.GroupPath)
 	dest.MaxJobDuration = types.Int64Value(int64(src.MaxJobDuration))
 	dest.TerraformVersion = types.StringValue(src.TerraformVersion)
 	dest.PreventDestroyPlan = types.BoolValue(src.PreventDestroyPlan)
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
+
+	dest.CurrentStateVersionID = types.StringNull()
+	if src.CurrentStateVersion != nil {
+		dest.CurrentStateVersionID = types.StringValue(src.CurrentStateVersion.Metadata.ID)
+	}
 }
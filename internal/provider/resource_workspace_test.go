@@ -36,10 +36,14 @@ func TestWorkspace(t *testing.T) {
 					resource.TestCheckResourceAttr("tharsis_workspace.tw", "max_job_duration", strconv.Itoa(createMaxJobDuration)),
 					resource.TestCheckResourceAttr("tharsis_workspace.tw", "terraform_version", createTerraformVersion),
 					resource.TestCheckResourceAttr("tharsis_workspace.tw", "prevent_destroy_plan", strconv.FormatBool(createPreventDestroyPlan)),
+					// A new workspace has had no runs.
+					resource.TestCheckNoResourceAttr("tharsis_workspace.tw", "current_state_version_id"),
 
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "id"),
 					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "last_updated"),
+					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "created_at"),
+					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "updated_at"),
 				),
 			},
 
@@ -67,6 +71,8 @@ func TestWorkspace(t *testing.T) {
 					// Verify dynamic values have any value set in the state.
 					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "id"),
 					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "last_updated"),
+					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "created_at"),
+					resource.TestCheckResourceAttrSet("tharsis_workspace.tw", "updated_at"),
 				),
 			},
 
@@ -2,7 +2,6 @@ package provider
 
 import (
 	"context"
-	"time"
 
 	"github.com/aws/smithy-go/ptr"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -10,7 +9,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/martian-cloud/terraform-provider-tharsis/internal/validators"
 	tharsis "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg"
 	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
 )
@@ -20,6 +21,10 @@ import (
 type WorkspaceVCSProviderLinkModel struct {
 	ID                  types.String   `tfsdk:"id"`
 	LastUpdated         types.String   `tfsdk:"last_updated"`
+	CreatedBy           types.String   `tfsdk:"created_by"`
+	CreatedAt           types.String   `tfsdk:"created_at"`
+	UpdatedAt           types.String   `tfsdk:"updated_at"`
+	MetadataVersion     types.String   `tfsdk:"metadata_version"`
 	WorkspaceID         types.String   `tfsdk:"workspace_id"`
 	WorkspacePath       types.String   `tfsdk:"workspace_path"`
 	VCSProviderID       types.String   `tfsdk:"vcs_provider_id"`
@@ -35,9 +40,10 @@ type WorkspaceVCSProviderLinkModel struct {
 
 // Ensure provider defined types fully satisfy framework interfaces
 var (
-	_ resource.Resource                = (*workspaceVCSProviderLinkResource)(nil)
-	_ resource.ResourceWithConfigure   = (*workspaceVCSProviderLinkResource)(nil)
-	_ resource.ResourceWithImportState = (*workspaceVCSProviderLinkResource)(nil)
+	_ resource.Resource                 = (*workspaceVCSProviderLinkResource)(nil)
+	_ resource.ResourceWithConfigure    = (*workspaceVCSProviderLinkResource)(nil)
+	_ resource.ResourceWithImportState  = (*workspaceVCSProviderLinkResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*workspaceVCSProviderLinkResource)(nil)
 )
 
 // NewWorkspaceVCSProviderLinkResource is a helper function to simplify the provider implementation.
@@ -47,6 +53,7 @@ func NewWorkspaceVCSProviderLinkResource() resource.Resource {
 
 type workspaceVCSProviderLinkResource struct {
 	client *tharsis.Client
+	paths  namespacePaths
 }
 
 // Metadata returns the full name of the resource, including prefix, underscore, instance name.
@@ -62,7 +69,7 @@ func (t *workspaceVCSProviderLinkResource) Schema(_ context.Context, _ resource.
 	description := "Defines and manages a workspace VCS provider link."
 
 	resp.Schema = schema.Schema{
-		Version:             1,
+		Version:             2,
 		MarkdownDescription: description,
 		Description:         description,
 		Attributes: map[string]schema.Attribute{
@@ -86,6 +93,9 @@ func (t *workspaceVCSProviderLinkResource) Schema(_ context.Context, _ resource.
 				MarkdownDescription: "The resource path of the workspace.",
 				Description:         "The resource path of the workspace.",
 				Required:            true,
+				Validators: []validator.String{
+					validators.RelativeResourcePath(),
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -157,9 +167,20 @@ func (t *workspaceVCSProviderLinkResource) Schema(_ context.Context, _ resource.
 				MarkdownDescription: "Timestamp when this workspace VCS provider link was most recently updated.",
 				Description:         "Timestamp when this workspace VCS provider link was most recently updated.",
 				Computed:            true,
+				DeprecationMessage:  lastUpdatedDeprecation,
 			},
 		},
 	}
+
+	resp.Schema.Attributes["created_by"] = createdByAttribute("workspace VCS provider link")
+	for name, attribute := range metadataAttributes("workspace VCS provider link") {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+// UpgradeState upgrades the state from prior schema versions.
+func (t *workspaceVCSProviderLinkResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return upgradeTimestampsState("tharsis_workspace_vcs_provider_link", "last_updated")
 }
 
 // Configure lets the provider implement the ResourceWithConfigure interface.
@@ -169,7 +190,9 @@ func (t *workspaceVCSProviderLinkResource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	t.client = req.ProviderData.(*tharsis.Client)
+	p := req.ProviderData.(*tharsisProvider)
+	t.client = p.client
+	t.paths = p.paths
 }
 
 func (t *workspaceVCSProviderLinkResource) Create(ctx context.Context,
@@ -203,7 +226,7 @@ func (t *workspaceVCSProviderLinkResource) Create(ctx context.Context,
 		&ttypes.CreateWorkspaceVCSProviderLinkInput{
 			ModuleDirectory:     moduleDirectory,
 			RepositoryPath:      workspaceVCSProviderLink.RepositoryPath.ValueString(),
-			WorkspacePath:       workspaceVCSProviderLink.WorkspacePath.ValueString(),
+			WorkspacePath:       t.paths.resolve(workspaceVCSProviderLink.WorkspacePath.ValueString()),
 			ProviderID:          workspaceVCSProviderLink.VCSProviderID.ValueString(),
 			Branch:              branch,
 			TagRegex:            tagRegex,
@@ -212,10 +235,7 @@ func (t *workspaceVCSProviderLinkResource) Create(ctx context.Context,
 			WebhookDisabled:     workspaceVCSProviderLink.WebhookDisabled.ValueBool(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating workspace VCS provider link",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error creating workspace VCS provider link", err, workspaceVCSProviderLink.WorkspacePath.ValueString()))
 		return
 	}
 
@@ -242,14 +262,11 @@ func (t *workspaceVCSProviderLinkResource) Read(ctx context.Context,
 	})
 	if err != nil {
 		if tharsis.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+			removeMissingResource(ctx, resp, "workspace VCS provider link", state.WorkspacePath.ValueString())
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error reading workspace VCS provider link",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error reading workspace VCS provider link", err, state.WorkspacePath.ValueString()))
 		return
 	}
 
@@ -299,10 +316,7 @@ func (t *workspaceVCSProviderLinkResource) Update(ctx context.Context,
 			WebhookDisabled:     plan.WebhookDisabled.ValueBool(),
 		})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating workspace VCS provider link",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error updating workspace VCS provider link", err, plan.WorkspacePath.ValueString()))
 		return
 	}
 
@@ -336,10 +350,7 @@ func (t *workspaceVCSProviderLinkResource) Delete(ctx context.Context,
 			return
 		}
 
-		resp.Diagnostics.AddError(
-			"Error deleting workspace VCS provider link",
-			err.Error(),
-		)
+		resp.Diagnostics.Append(apiErrorDiagnostic("Error deleting workspace VCS provider link", err, state.WorkspacePath.ValueString()))
 	}
 }
 
@@ -358,7 +369,7 @@ func (t *workspaceVCSProviderLinkResource) copyWorkspaceVCSProviderLink(src ttyp
 ) {
 	dest.ID = types.StringValue(src.Metadata.ID)
 	dest.WorkspaceID = types.StringValue(src.WorkspaceID)
-	dest.WorkspacePath = types.StringValue(src.WorkspacePath)
+	dest.WorkspacePath = t.paths.keep(dest.WorkspacePath, src.WorkspacePath)
 	dest.VCSProviderID = types.StringValue(src.VCSProviderID)
 	dest.RepositoryPath = types.StringValue(src.RepositoryPath)
 	dest.WebhookID = t.stringValueFromStringPtr(src.WebhookID)
@@ -373,7 +384,9 @@ func (t *workspaceVCSProviderLinkResource) copyWorkspaceVCSProviderLink(src ttyp
 	dest.WebhookDisabled = types.BoolValue(src.WebhookDisabled)
 
 	// Must use time value from SDK/API.  Using time.Now() is not reliable.
-	dest.LastUpdated = types.StringValue(src.Metadata.LastUpdatedTimestamp.Format(time.RFC850))
+	dest.LastUpdated = formatTimestamp(src.Metadata.LastUpdatedTimestamp)
+	dest.CreatedBy = types.StringValue(src.CreatedBy)
+	copyMetadata(src.Metadata, &dest.CreatedAt, &dest.UpdatedAt, &dest.MetadataVersion)
 }
 
 // stringValueFromStringPtr produces a types.StringValue from a *string that might be nil.
@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// Classes of transient errors that can be retried.
+const (
+	retryClassServerError = "server_error"
+	retryClassRateLimit   = "rate_limit"
+	retryClassNetwork     = "network"
+)
+
+// The SDK's HTTP client already retries each request up to 3 times, waiting 10 to 60 seconds,
+// so the defaults only add one more attempt for errors that outlast those retries.
+const (
+	// defaultRetryMaxAttempts is the default number of attempts, including the first.
+	defaultRetryMaxAttempts = 2
+
+	// defaultRetryInitialBackoff is the default delay before the first retry.
+	defaultRetryInitialBackoff = time.Second
+
+	// defaultRetryMaxBackoff is the default upper bound on the delay between retries.
+	defaultRetryMaxBackoff = 10 * time.Second
+)
+
+// retryClasses are the retryable error classes, in the order they are documented.
+var retryClasses = []string{retryClassServerError, retryClassRateLimit, retryClassNetwork}
+
+// retryPolicy controls how API calls that fail with transient errors are retried.
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	classes        map[string]bool
+}
+
+// defaultRetryPolicy returns the policy used when none is configured, which retries every class.
+func defaultRetryPolicy() retryPolicy {
+	classes := map[string]bool{}
+	for _, class := range retryClasses {
+		classes[class] = true
+	}
+
+	return retryPolicy{
+		maxAttempts:    defaultRetryMaxAttempts,
+		initialBackoff: defaultRetryInitialBackoff,
+		maxBackoff:     defaultRetryMaxBackoff,
+		classes:        classes,
+	}
+}
+
+// backoff returns the delay before the given retry, doubling from the initial backoff up to the maximum.
+func (p retryPolicy) backoff(retry int) time.Duration {
+	delay := p.initialBackoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if delay >= p.maxBackoff {
+			return p.maxBackoff
+		}
+	}
+
+	if delay > p.maxBackoff {
+		return p.maxBackoff
+	}
+	return delay
+}
+
+// withRetry calls fn until it succeeds, fails with an error that isn't retryable,
+// runs out of attempts or the context expires.  The last error is returned.
+func withRetry(ctx context.Context, policy retryPolicy, operation string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		class := classifyError(err)
+		if attempt >= policy.maxAttempts || class == "" || !policy.classes[class] || ctx.Err() != nil {
+			return err
+		}
+
+		delay := policy.backoff(attempt)
+		tflog.Warn(ctx, "Retrying after transient error", map[string]any{
+			"operation": operation,
+			"attempt":   attempt,
+			"class":     class,
+			"delay":     delay.String(),
+			"error":     err.Error(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// classifyError returns the retry class of an error, or an empty string if it isn't transient.
+// API errors are classified by the code of the SDK's error.
+func classifyError(err error) string {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ""
+	}
+
+	var tErr *ttypes.Error
+	if errors.As(err, &tErr) {
+		switch tErr.Code {
+		case ttypes.ErrTooManyRequests:
+			return retryClassRateLimit
+		case ttypes.ErrServiceUnavailable:
+			return retryClassServerError
+		}
+	}
+
+	// The SDK wraps errors from the HTTP client, such as network errors, in an internal error.
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return retryClassNetwork
+	}
+
+	return ""
+}
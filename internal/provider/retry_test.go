@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	ttypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+func Test_classifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "Service unavailable is a server error",
+			err:  fmt.Errorf("failed to get run: %w", &ttypes.Error{Code: ttypes.ErrServiceUnavailable}),
+			want: retryClassServerError,
+		},
+		{
+			name: "Too many requests is a rate limit",
+			err:  &ttypes.Error{Code: ttypes.ErrTooManyRequests, Msg: "rate limit exceeded"},
+			want: retryClassRateLimit,
+		},
+		{
+			name: "Unexpected EOF is a network error",
+			err:  fmt.Errorf("failed to read response: %w", io.ErrUnexpectedEOF),
+			want: retryClassNetwork,
+		},
+		{
+			name: "Network error wrapped by the SDK is a network error",
+			err:  &ttypes.Error{Code: ttypes.ErrInternal, Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}},
+			want: retryClassNetwork,
+		},
+		{
+			name: "Other API errors are not retryable",
+			err:  &ttypes.Error{Code: ttypes.ErrInternal, Msg: "workspace 503 not found"},
+			want: "",
+		},
+		{
+			name: "Context cancellation is not retryable",
+			err:  fmt.Errorf("request failed: %w", context.Canceled),
+			want: "",
+		},
+		{
+			name: "Other errors are not retryable",
+			err:  errors.New("workspace not found"),
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.want {
+				t.Errorf("classifyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_withRetry(t *testing.T) {
+	policy := defaultRetryPolicy()
+	policy.maxAttempts = 3
+	policy.initialBackoff = time.Millisecond
+	policy.maxBackoff = time.Millisecond
+	unavailable := &ttypes.Error{Code: ttypes.ErrServiceUnavailable}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "Succeeds without retrying",
+			errs:      []error{nil},
+			wantCalls: 1,
+			wantErr:   false,
+		},
+		{
+			name:      "Retries transient errors until success",
+			errs:      []error{unavailable, &ttypes.Error{Code: ttypes.ErrTooManyRequests}, nil},
+			wantCalls: 3,
+			wantErr:   false,
+		},
+		{
+			name:      "Gives up after the maximum attempts",
+			errs:      []error{unavailable, unavailable, unavailable, nil},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "Doesn't retry other errors",
+			errs:      []error{errors.New("workspace not found"), nil},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := withRetry(context.Background(), policy, "test", func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("withRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("withRetry() calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func Test_retryPolicy_backoff(t *testing.T) {
+	policy := retryPolicy{initialBackoff: time.Second, maxBackoff: 5 * time.Second}
+
+	for retry, want := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second,
+	} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", retry, got, want)
+		}
+	}
+}
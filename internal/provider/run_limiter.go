@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// runLimiterProgressInterval is how often a queued run logs its position in the queue.
+const runLimiterProgressInterval = 30 * time.Second
+
+// runLimiter limits how many runs the provider launches at once, across all tharsis_apply_module resources.
+// Runs wait for a slot in the order they asked for one.  A nil runLimiter doesn't limit anything.
+type runLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	active int
+	queue  []chan struct{}
+}
+
+// newRunLimiter returns a limiter allowing up to limit concurrent runs, or nil if limit isn't positive.
+func newRunLimiter(limit int) *runLimiter {
+	if limit <= 0 {
+		return nil
+	}
+
+	return &runLimiter{limit: limit}
+}
+
+// acquire waits for a run slot, logging the caller's position in the queue while it waits.
+// The returned release function must be called once the run is done.
+func (l *runLimiter) acquire(ctx context.Context, fields map[string]any) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	if (l.active < l.limit) && (len(l.queue) == 0) {
+		l.active++
+		l.mu.Unlock()
+		return l.release, nil
+	}
+
+	ready := make(chan struct{})
+	l.queue = append(l.queue, ready)
+	l.mu.Unlock()
+
+	ticker := time.NewTicker(runLimiterProgressInterval)
+	defer ticker.Stop()
+
+	l.logPosition(ctx, ready, fields)
+	for {
+		select {
+		case <-ready:
+			return l.release, nil
+		case <-ticker.C:
+			l.logPosition(ctx, ready, fields)
+		case <-ctx.Done():
+			if !l.dequeue(ready) {
+				// The slot was handed over just as the context expired, so pass it on.
+				l.release()
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// release hands the caller's slot to the first queued run, if any.
+func (l *runLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.queue) > 0 {
+		next := l.queue[0]
+		l.queue = l.queue[1:]
+		close(next)
+		return
+	}
+
+	l.active--
+}
+
+// dequeue removes a waiting run from the queue, returning false if it had already been handed a slot.
+func (l *runLimiter) dequeue(ready chan struct{}) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i, waiting := range l.queue {
+		if waiting == ready {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// logPosition logs a waiting run's position in the queue.
+func (l *runLimiter) logPosition(ctx context.Context, ready chan struct{}, fields map[string]any) {
+	l.mu.Lock()
+	position := 0
+	for i, waiting := range l.queue {
+		if waiting == ready {
+			position = i + 1
+			break
+		}
+	}
+	l.mu.Unlock()
+
+	if position == 0 {
+		return
+	}
+
+	logFields := map[string]any{"queue_position": position, "max_concurrent_runs": l.limit}
+	for k, v := range fields {
+		logFields[k] = v
+	}
+	tflog.Info(ctx, "Waiting for another run to finish before launching a run", logFields)
+}
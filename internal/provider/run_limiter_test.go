@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_runLimiter(t *testing.T) {
+	limiter := newRunLimiter(1)
+
+	release1, err := limiter.acquire(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	// A second run must wait until the first is released.
+	acquired := make(chan func())
+	go func() {
+		release2, err := limiter.acquire(context.Background(), nil)
+		if err != nil {
+			t.Errorf("acquire() error = %v", err)
+		}
+		acquired <- release2
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquire() did not wait for a slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A run whose context expires gives up its place in the queue.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = limiter.acquire(ctx, nil); err == nil {
+		t.Error("acquire() expected an error when the context expires")
+	}
+
+	release1()
+
+	select {
+	case release2 := <-acquired:
+		release2()
+	case <-time.After(time.Second):
+		t.Fatal("acquire() did not get the released slot")
+	}
+
+	if limiter.active != 0 || len(limiter.queue) != 0 {
+		t.Errorf("limiter has %d active and %d queued runs, want none", limiter.active, len(limiter.queue))
+	}
+}
+
+func Test_newRunLimiter(t *testing.T) {
+	if limiter := newRunLimiter(0); limiter != nil {
+		t.Errorf("newRunLimiter(0) = %v, want nil", limiter)
+	}
+
+	// A nil limiter never waits.
+	var limiter *runLimiter
+	release, err := limiter.acquire(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	release()
+}
@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdktypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// workspaceCacheTTL is how long a workspace read while refreshing is reused by other resources.
+// It's long enough to cover the refresh of a large configuration, and short enough that a later
+// operation by a long-lived provider, such as in a test, reads the workspace again.
+const workspaceCacheTTL = 30 * time.Second
+
+// workspaceCache is a workspaceService that shares workspaces between resources and data sources that
+// read them while refreshing, so the number of GetWorkspace calls scales with the number of distinct
+// workspaces rather than the number of resources.  A workspace is cached by both its ID and its path,
+// and concurrent lookups of the same workspace share one call.  Errors aren't cached.
+//
+// It's only for reads that can be a little stale.  Anything that waits for a workspace to change,
+// or reads it after changing it, uses the SDK directly.
+type workspaceCache struct {
+	service workspaceService
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*workspaceCacheEntry
+}
+
+// workspaceCacheEntry is a workspace that has been read, or is being read, by a lookup.
+type workspaceCacheEntry struct {
+	done      chan struct{}
+	workspace *sdktypes.Workspace
+	err       error
+	expires   time.Time
+}
+
+// newWorkspaceCache returns a cache of the workspaces read with service, or nil if there's no service.
+func newWorkspaceCache(service workspaceService) *workspaceCache {
+	if service == nil {
+		return nil
+	}
+
+	return &workspaceCache{
+		service: service,
+		now:     time.Now,
+		entries: map[string]*workspaceCacheEntry{},
+	}
+}
+
+// GetWorkspace returns the workspace with the path or ID in input, reading it only if it isn't cached.
+func (c *workspaceCache) GetWorkspace(ctx context.Context,
+	input *sdktypes.GetWorkspaceInput,
+) (*sdktypes.Workspace, error) {
+	key := workspaceCacheKey(input)
+	if key == "" {
+		return c.service.GetWorkspace(ctx, input)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok {
+		select {
+		case <-entry.done:
+			if c.now().After(entry.expires) {
+				ok = false
+			}
+		default:
+			// Another lookup is reading it.
+		}
+	}
+	if !ok {
+		entry = &workspaceCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+		c.mu.Unlock()
+
+		c.fill(ctx, key, entry, input)
+		return entry.workspace, entry.err
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-entry.done:
+		return entry.workspace, entry.err
+	}
+}
+
+// fill reads the workspace of an entry, and caches it by both its ID and path.
+// An entry that fails isn't kept, so the next lookup tries again.
+func (c *workspaceCache) fill(ctx context.Context, key string, entry *workspaceCacheEntry,
+	input *sdktypes.GetWorkspaceInput,
+) {
+	entry.workspace, entry.err = c.service.GetWorkspace(ctx, input)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.expires = c.now().Add(workspaceCacheTTL)
+	close(entry.done)
+
+	if (entry.err != nil) || (entry.workspace == nil) {
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		return
+	}
+
+	c.entries["id:"+entry.workspace.Metadata.ID] = entry
+	c.entries["path:"+entry.workspace.FullPath] = entry
+}
+
+// forget removes a workspace from the cache, by its ID or path, once the provider has changed it.
+// It's safe to call on a nil cache.
+func (c *workspaceCache) forget(input *sdktypes.GetWorkspaceInput) {
+	if c == nil {
+		return
+	}
+
+	key := workspaceCacheKey(input)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	// Remove the entry under its other key too.
+	for k, e := range c.entries {
+		if e == entry {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// workspaceCacheKey returns the cache key of the workspace to get, or an empty string if there's no path or ID.
+func workspaceCacheKey(input *sdktypes.GetWorkspaceInput) string {
+	switch {
+	case input.ID != nil:
+		return "id:" + *input.ID
+	case input.Path != nil:
+		return "path:" + *input.Path
+	default:
+		return ""
+	}
+}
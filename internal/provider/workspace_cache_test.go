@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go/ptr"
+	sdktypes "gitlab.com/infor-cloud/martian-cloud/tharsis/tharsis-sdk-go/pkg/types"
+)
+
+// countingWorkspaceService returns a workspace, or an error, and counts the calls.
+type countingWorkspaceService struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (s *countingWorkspaceService) GetWorkspace(_ context.Context,
+	_ *sdktypes.GetWorkspaceInput,
+) (*sdktypes.Workspace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &sdktypes.Workspace{
+		Metadata: sdktypes.ResourceMetadata{ID: "ws-1"},
+		FullPath: "top/ws",
+	}, nil
+}
+
+func Test_workspaceCache(t *testing.T) {
+	service := &countingWorkspaceService{}
+	cache := newWorkspaceCache(service)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Concurrent lookups by path and ID share the workspace.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.GetWorkspace(ctx, &sdktypes.GetWorkspaceInput{Path: ptr.String("top/ws")}); err != nil {
+				t.Errorf("GetWorkspace() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	ws, err := cache.GetWorkspace(ctx, &sdktypes.GetWorkspaceInput{ID: ptr.String("ws-1")})
+	if err != nil || ws.FullPath != "top/ws" {
+		t.Fatalf("GetWorkspace() by ID = %v, %v", ws, err)
+	}
+	if service.calls != 1 {
+		t.Errorf("calls = %d, want 1", service.calls)
+	}
+
+	// A forgotten workspace is read again, by either key.
+	cache.forget(&sdktypes.GetWorkspaceInput{Path: ptr.String("top/ws")})
+	if _, err = cache.GetWorkspace(ctx, &sdktypes.GetWorkspaceInput{ID: ptr.String("ws-1")}); err != nil {
+		t.Fatalf("GetWorkspace() error = %v", err)
+	}
+	if service.calls != 2 {
+		t.Errorf("calls after forget = %d, want 2", service.calls)
+	}
+
+	// An expired workspace is read again.
+	now = now.Add(workspaceCacheTTL + time.Second)
+	if _, err = cache.GetWorkspace(ctx, &sdktypes.GetWorkspaceInput{ID: ptr.String("ws-1")}); err != nil {
+		t.Fatalf("GetWorkspace() error = %v", err)
+	}
+	if service.calls != 3 {
+		t.Errorf("calls after expiry = %d, want 3", service.calls)
+	}
+}
+
+func Test_workspaceCache_errorsArentCached(t *testing.T) {
+	service := &countingWorkspaceService{err: errors.New("unavailable")}
+	cache := newWorkspaceCache(service)
+	input := &sdktypes.GetWorkspaceInput{Path: ptr.String("top/ws")}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.GetWorkspace(context.Background(), input); err == nil {
+			t.Fatal("GetWorkspace() error = nil, want an error")
+		}
+	}
+	if service.calls != 2 {
+		t.Errorf("calls = %d, want 2", service.calls)
+	}
+}
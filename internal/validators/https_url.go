@@ -0,0 +1,43 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = httpsURLValidator{}
+
+// httpsURLValidator is a validator that requires a string attribute to be an absolute HTTPS URL.
+type httpsURLValidator struct{}
+
+// HTTPSURL returns a validator that requires the value to be an HTTPS URL, such as an OIDC issuer.
+func HTTPSURL() validator.String {
+	return httpsURLValidator{}
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v httpsURLValidator) Description(_ context.Context) string {
+	return "value must be a URL starting with https://"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v httpsURLValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a URL starting with `https://`"
+}
+
+// ValidateString runs the logic of the validator.
+func (v httpsURLValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Unknown values are validated once they're known.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if parsed, err := url.Parse(value); err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid URL",
+			fmt.Sprintf("%q is not valid: %s.", value, v.Description(ctx)))
+	}
+}
@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.Map = nonEmptyMapValidator{}
+
+// nonEmptyMapValidator is a validator that requires a map of strings to have at least one element,
+// and no empty values.
+type nonEmptyMapValidator struct{}
+
+// NonEmptyMap returns a validator that requires a map of strings to have at least one element, and no empty values.
+func NonEmptyMap() validator.Map {
+	return nonEmptyMapValidator{}
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v nonEmptyMapValidator) Description(_ context.Context) string {
+	return "map must have at least one element, and no empty values"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v nonEmptyMapValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateMap runs the logic of the validator.
+func (v nonEmptyMapValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	// Unknown values are validated once they're known.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	elements := req.ConfigValue.Elements()
+	if len(elements) == 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Empty map", fmt.Sprintf("The %s.", v.Description(ctx)))
+		return
+	}
+
+	for key, element := range elements {
+		if value, ok := element.(types.String); ok && !value.IsUnknown() && (value.IsNull() || value.ValueString() == "") {
+			resp.Diagnostics.AddAttributeError(req.Path.AtMapKey(key), "Empty value",
+				fmt.Sprintf("The value of %q must not be empty.", key))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package validators contains plan-time validators for attributes whose
+// values must be in a format Tharsis accepts.
+package validators
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = oneOfValidator{}
+
+// oneOfValidator is a validator that requires a string attribute to be one of a fixed set of values.
+type oneOfValidator struct {
+	Values []string
+}
+
+// OneOf returns a validator that requires the value to be one of the specified values.
+func OneOf(values ...string) validator.String {
+	return oneOfValidator{
+		Values: values,
+	}
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v oneOfValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: %s", strings.Join(v.Values, ", "))
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v oneOfValidator) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("value must be one of: `%s`", strings.Join(v.Values, "`, `"))
+}
+
+// ValidateString runs the logic of the validator.
+func (v oneOfValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Unknown values are validated once they're known.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	for _, allowed := range v.Values {
+		if value == allowed {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid attribute value",
+		fmt.Sprintf("%q is not valid: %s.", value, v.Description(ctx)))
+}
@@ -0,0 +1,82 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = resourcePathValidator{}
+
+// namePattern matches the name of a group or workspace: lowercase letters, digits,
+// hyphens and underscores, starting and ending with a letter or digit.
+var namePattern = regexp.MustCompile(`^[0-9a-z](?:[0-9a-z\-_]{0,62}[0-9a-z])?$`)
+
+// relativePrefix marks a path as relative to the provider's default group path.
+const relativePrefix = "./"
+
+// resourcePathValidator is a validator that requires a string attribute to be the full path
+// of a group or workspace, such as top-level-group/sub-group/workspace.
+type resourcePathValidator struct {
+	// allowRelative allows a path starting with ./, which is relative to the provider's default group path.
+	allowRelative bool
+}
+
+// ResourcePath returns a validator that requires the value to be a group or workspace path.
+func ResourcePath() validator.String {
+	return resourcePathValidator{}
+}
+
+// RelativeResourcePath returns a validator that requires the value to be a group or workspace path,
+// or a path starting with ./ that is relative to the provider's default group path.
+func RelativeResourcePath() validator.String {
+	return resourcePathValidator{allowRelative: true}
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v resourcePathValidator) Description(_ context.Context) string {
+	description := "value must be a path of names separated by slashes, each of lowercase letters, digits, hyphens and underscores"
+	if v.allowRelative {
+		description += ", optionally starting with ./"
+	}
+
+	return description
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v resourcePathValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString runs the logic of the validator.
+func (v resourcePathValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Unknown values are validated once they're known.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	trimmed := value
+	if v.allowRelative {
+		trimmed = strings.TrimPrefix(trimmed, relativePrefix)
+	}
+
+	if !IsResourcePath(trimmed) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid path",
+			fmt.Sprintf("%q is not a valid path: %s.", value, v.Description(ctx)))
+	}
+}
+
+// IsResourcePath returns whether a value is a group or workspace path, or a relative path of names.
+func IsResourcePath(value string) bool {
+	for _, name := range strings.Split(value, "/") {
+		if !namePattern.MatchString(name) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,47 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ validator.String = semverValidator{}
+
+// semverPattern matches a semantic version, as defined by https://semver.org.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// semverValidator is a validator that requires a string attribute to be a semantic version.
+type semverValidator struct{}
+
+// Semver returns a validator that requires the value to be a semantic version, such as 1.2.3.
+func Semver() validator.String {
+	return semverValidator{}
+}
+
+// Description returns a plain text description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v semverValidator) Description(_ context.Context) string {
+	return "value must be a semantic version, such as 1.2.3"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v semverValidator) MarkdownDescription(_ context.Context) string {
+	return "value must be a semantic version, such as `1.2.3`"
+}
+
+// ValidateString runs the logic of the validator.
+func (v semverValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	// Unknown values are validated once they're known.
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if value := req.ConfigValue.ValueString(); !semverPattern.MatchString(value) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid version",
+			fmt.Sprintf("%q is not valid: %s.", value, v.Description(ctx)))
+	}
+}
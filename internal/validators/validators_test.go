@@ -0,0 +1,173 @@
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator validator.String
+		value     types.String
+		wantErr   bool
+	}{
+		{
+			name:      "Nested group path is valid",
+			validator: ResourcePath(),
+			value:     types.StringValue("top-group/sub_group/workspace-1"),
+		},
+		{
+			name:      "Path with a trailing slash is invalid",
+			validator: ResourcePath(),
+			value:     types.StringValue("top-group/"),
+			wantErr:   true,
+		},
+		{
+			name:      "Path with upper case letters is invalid",
+			validator: ResourcePath(),
+			value:     types.StringValue("Top-Group"),
+			wantErr:   true,
+		},
+		{
+			name:      "Name ending with a hyphen is invalid",
+			validator: ResourcePath(),
+			value:     types.StringValue("top-group/workspace-"),
+			wantErr:   true,
+		},
+		{
+			name:      "Relative path is invalid",
+			validator: ResourcePath(),
+			value:     types.StringValue("./sub-group/workspace-1"),
+			wantErr:   true,
+		},
+		{
+			name:      "Relative path is valid where allowed",
+			validator: RelativeResourcePath(),
+			value:     types.StringValue("./sub-group/workspace-1"),
+		},
+		{
+			name:      "Parent of a relative path is invalid",
+			validator: RelativeResourcePath(),
+			value:     types.StringValue("../workspace-1"),
+			wantErr:   true,
+		},
+		{
+			name:      "Unknown path isn't validated",
+			validator: ResourcePath(),
+			value:     types.StringUnknown(),
+		},
+		{
+			name:      "Pre-release version is valid",
+			validator: Semver(),
+			value:     types.StringValue("1.2.3-rc.1+build.5"),
+		},
+		{
+			name:      "Version with a v prefix is invalid",
+			validator: Semver(),
+			value:     types.StringValue("v1.2.3"),
+			wantErr:   true,
+		},
+		{
+			name:      "Version constraint is invalid",
+			validator: Semver(),
+			value:     types.StringValue("~> 1.2"),
+			wantErr:   true,
+		},
+		{
+			name:      "Null version isn't validated",
+			validator: Semver(),
+			value:     types.StringNull(),
+		},
+		{
+			name:      "Allowed value is valid",
+			validator: OneOf("terraform", "environment"),
+			value:     types.StringValue("environment"),
+		},
+		{
+			name:      "Values are case sensitive",
+			validator: OneOf("terraform", "environment"),
+			value:     types.StringValue("Terraform"),
+			wantErr:   true,
+		},
+		{
+			name:      "HTTPS URL is valid",
+			validator: HTTPSURL(),
+			value:     types.StringValue("https://gitlab.example.com"),
+		},
+		{
+			name:      "HTTP URL is invalid",
+			validator: HTTPSURL(),
+			value:     types.StringValue("http://gitlab.example.com"),
+			wantErr:   true,
+		},
+		{
+			name:      "Host name without a scheme is invalid",
+			validator: HTTPSURL(),
+			value:     types.StringValue("gitlab.example.com"),
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			tt.validator.ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("test"),
+				ConfigValue: tt.value,
+			}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("ValidateString() error = %v, want %v: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestNonEmptyMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   types.Map
+		wantErr bool
+	}{
+		{
+			name: "Map with values is valid",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"sub": types.StringValue("project_path:my-group/my-project"),
+			}),
+		},
+		{
+			name:    "Empty map is invalid",
+			value:   types.MapValueMust(types.StringType, map[string]attr.Value{}),
+			wantErr: true,
+		},
+		{
+			name: "Empty value is invalid",
+			value: types.MapValueMust(types.StringType, map[string]attr.Value{
+				"sub": types.StringValue(""),
+			}),
+			wantErr: true,
+		},
+		{
+			name:  "Unknown map isn't validated",
+			value: types.MapUnknown(types.StringType),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &validator.MapResponse{}
+			NonEmptyMap().ValidateMap(context.Background(), validator.MapRequest{
+				Path:        path.Root("test"),
+				ConfigValue: tt.value,
+			}, resp)
+
+			if got := resp.Diagnostics.HasError(); got != tt.wantErr {
+				t.Errorf("ValidateMap() error = %v, want %v: %v", got, tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}